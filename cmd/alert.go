@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/jedib0t/go-pretty/table"
 	"github.com/spf13/cobra"
@@ -212,8 +213,9 @@ var alertServiceCmd = &cobra.Command{
 	Use:   "cloudwatcher",
 	Short: "Bizfly Cloud Watcher Interaction",
 	Long:  `Interact with Cloud Watcher Service. Allow do CRUD alarms, receivers, ...`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Interacting with cloud watcher service")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logging.Verbosef("Interacting with cloud watcher service")
+		return nil
 	},
 }
 
@@ -225,8 +227,9 @@ var agentCmd = &cobra.Command{
 	Use:   "agent",
 	Short: "Bizfly Cloud Watcher Interaction with agent resources",
 	Long:  `Interact with Cloud Watcher Service. Allow do CRUD alarms, agents, ...`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Interacting with cloud watcher service")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logging.Verbosef("Interacting with cloud watcher service")
+		return nil
 	},
 }
 
@@ -234,11 +237,14 @@ var agentListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List agents",
 	Long:  "List agents in your account",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		agents, err := client.CloudWatcher.Agents().List(ctx, nil)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
@@ -246,7 +252,7 @@ var agentListCmd = &cobra.Command{
 			s := table.Row{agent.ID, agent.Name, agent.Runtime, agent.Online}
 			data = append(data, s)
 		}
-		formatter.SimpleOutput(agentListHeader, data)
+		return formatter.SimpleOutput(agentListHeader, data, agents)
 	},
 }
 
@@ -254,29 +260,32 @@ var agentShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show detail agent",
 	Long:  "Show detail agent by agent ID",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		agent, err := client.CloudWatcher.Agents().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var jsonSecretData = make(map[string]interface{})
 		byteData, err := json.Marshal(agent)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonSecretData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonSecretData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, agent)
 	},
 }
 
@@ -284,17 +293,21 @@ var agentDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a agent",
 	Long:  "Delete a agent by agent ID",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.CloudWatcher.Agents().Delete(ctx, args[0])
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = client.CloudWatcher.Agents().Delete(ctx, args[0])
+		if err != nil {
+			return err
 		}
 
-		log.Printf("Doing delete agent with ID: %v", args[0])
+		logging.Infof("Doing delete agent with ID: %v", args[0])
+		return nil
 	},
 }
 
@@ -306,8 +319,9 @@ var alarmCmd = &cobra.Command{
 	Use:   "alarm",
 	Short: "Bizfly Cloud Watcher Interaction with alarm resources",
 	Long:  `Interact with Cloud Watcher Service. Allow do CRUD alarms, receivers, ...`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Interacting with cloud watcher service")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logging.Verbosef("Interacting with cloud watcher service")
+		return nil
 	},
 }
 
@@ -315,11 +329,14 @@ var alarmListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List alarms",
 	Long:  "List alarms in your account",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		alarms, err := client.CloudWatcher.Alarms().List(ctx, nil)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
@@ -327,7 +344,7 @@ var alarmListCmd = &cobra.Command{
 			s := table.Row{alarm.ID, alarm.Name, alarm.ResourceType, fmt.Sprintf("%v", alarm.Enable)}
 			data = append(data, s)
 		}
-		formatter.SimpleOutput(alarmListHeader, data)
+		return formatter.SimpleOutput(alarmListHeader, data, alarms)
 	},
 }
 
@@ -335,7 +352,7 @@ var alarmCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create an alarm",
 	Long:  "Create an alarm",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Parse receivers from raw input
 		var rawReceivers = []map[string]interface{}{}
 		for _, alarmReceiver := range alarmReceivers {
@@ -345,10 +362,10 @@ var alarmCreateCmd = &cobra.Command{
 				z := strings.Split(parentValue, "=")
 				// Validate data from input
 				if z[0] == "" {
-					log.Fatal("Not found keyword for: ", z[1])
+					return usageError("Not found keyword for: %s", z[1])
 				}
 				if len(z[1]) == 0 {
-					log.Fatal("Have error value for: ", z[0])
+					return usageError("Have error value for: %s", z[0])
 				}
 
 				if strings.Contains(z[1], ",") {
@@ -358,10 +375,10 @@ var alarmCreateCmd = &cobra.Command{
 				}
 			}
 			if _, ok := rawReceiver["id"]; !ok {
-				log.Fatal("id of receiver is required")
+				return usageError("id of receiver is required")
 			}
 			if _, ok := rawReceiver["methods"]; !ok {
-				log.Fatal("methods of receiver is required")
+				return usageError("methods of receiver is required")
 			}
 			rawReceivers = append(rawReceivers, rawReceiver)
 
@@ -369,11 +386,14 @@ var alarmCreateCmd = &cobra.Command{
 
 		// Do make []gobizfly.AlarmReceiversUse
 		var alarmCreateReceivers = []gobizfly.AlarmReceiversUse{}
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		for _, rawReceiver := range rawReceivers {
 			receiver, err := client.CloudWatcher.Receivers().Get(ctx, rawReceiver["id"].(string))
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 
 			var acr = gobizfly.AlarmReceiversUse{
@@ -381,23 +401,33 @@ var alarmCreateCmd = &cobra.Command{
 				ReceiverID: receiver.ReceiverID,
 			}
 			if _, ok := SliceContains(rawReceiver["methods"], "telegram"); ok {
-				MethodsReceiverIsNull(receiver.ReceiverID, "telegram", receiver.TelegramChatID)
+				if err := MethodsReceiverIsNull(receiver.ReceiverID, "telegram", receiver.TelegramChatID); err != nil {
+					return err
+				}
 				acr.TelegramChatID = receiver.TelegramChatID
 			}
 			if _, ok := SliceContains(rawReceiver["methods"], "email"); ok {
-				MethodsReceiverIsNull(receiver.ReceiverID, "email", receiver.EmailAddress)
+				if err := MethodsReceiverIsNull(receiver.ReceiverID, "email", receiver.EmailAddress); err != nil {
+					return err
+				}
 				acr.EmailAddress = receiver.EmailAddress
 			}
 			if _, ok := SliceContains(rawReceiver["methods"], "webhook_url"); ok {
-				MethodsReceiverIsNull(receiver.ReceiverID, "webhook_url", receiver.WebhookURL)
+				if err := MethodsReceiverIsNull(receiver.ReceiverID, "webhook_url", receiver.WebhookURL); err != nil {
+					return err
+				}
 				acr.WebhookURL = receiver.WebhookURL
 			}
 			if _, ok := SliceContains(rawReceiver["methods"], "slack"); ok {
-				MethodsReceiverIsNull(receiver.ReceiverID, "slack", receiver.Slack.SlackChannelName)
+				if err := MethodsReceiverIsNull(receiver.ReceiverID, "slack", receiver.Slack.SlackChannelName); err != nil {
+					return err
+				}
 				acr.SlackChannelName = receiver.Slack.SlackChannelName
 			}
 			if _, ok := SliceContains(rawReceiver["methods"], "sms"); ok {
-				MethodsReceiverIsNull(receiver.ReceiverID, "sms", receiver.SMSNumber)
+				if err := MethodsReceiverIsNull(receiver.ReceiverID, "sms", receiver.SMSNumber); err != nil {
+					return err
+				}
 				acr.SMSNumber = receiver.SMSNumber
 				elem, ok := rawReceiver["sms_interval"]
 				if ok {
@@ -414,7 +444,7 @@ var alarmCreateCmd = &cobra.Command{
 		}
 
 		if len(alarmLoadBalancers) > 1 {
-			log.Fatal("UNSUPPORTED multiple load balancers")
+			return usageError("UNSUPPORTED multiple load balancers")
 		}
 		var rawLoadBalancers = []map[string]interface{}{}
 		for _, alarmLoadBalancer := range alarmLoadBalancers {
@@ -424,10 +454,10 @@ var alarmCreateCmd = &cobra.Command{
 				z := strings.Split(parentValue, "=")
 				// Validate data from input
 				if z[0] == "" {
-					log.Fatal("Not found keyword for: ", z[1])
+					return usageError("Not found keyword for: %s", z[1])
 				}
 				if len(z[1]) == 0 {
-					log.Fatal("Have error value for: ", z[0])
+					return usageError("Have error value for: %s", z[0])
 				}
 
 				if strings.Contains(z[1], ",") {
@@ -437,16 +467,16 @@ var alarmCreateCmd = &cobra.Command{
 				}
 			}
 			if _, ok := rawLoadBalancer["id"]; !ok {
-				log.Fatal("id of load balancer is required")
+				return usageError("id of load balancer is required")
 			}
 			if _, ok := rawLoadBalancer["tgid"]; !ok {
-				log.Fatal("id of backend/frontend of load balancer is required")
+				return usageError("id of backend/frontend of load balancer is required")
 			}
 			if _, ok := rawLoadBalancer["tgtype"]; !ok {
-				log.Fatal("type of tgid is required")
+				return usageError("type of tgid is required")
 			}
 			if _, ok := SliceContains(alarmLoadBalancersTarget, rawLoadBalancer["tgtype"]); !ok {
-				log.Fatal("type of tgid is unsupported")
+				return usageError("type of tgid is unsupported")
 			}
 			rawLoadBalancers = append(rawLoadBalancers, rawLoadBalancer)
 
@@ -455,7 +485,7 @@ var alarmCreateCmd = &cobra.Command{
 		for _, rawLoadBalancer := range rawLoadBalancers {
 			lb, err := client.CloudLoadBalancer.Get(ctx, rawLoadBalancer["id"].(string))
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 
 			var albm = gobizfly.AlarmLoadBalancersMonitor{
@@ -469,13 +499,13 @@ var alarmCreateCmd = &cobra.Command{
 			if rawLoadBalancer["tgtype"] == "frontend" {
 				frontend, err := client.CloudLoadBalancer.Listeners().Get(ctx, rawLoadBalancer["tgid"].(string))
 				if err != nil {
-					log.Fatal(err)
+					return err
 				}
 				albm.TargetName = frontend.Name
 			} else {
 				backend, err := client.CloudLoadBalancer.Pools().Get(ctx, rawLoadBalancer["tgid"].(string))
 				if err != nil {
-					log.Fatal(err)
+					return err
 				}
 				albm.TargetName = backend.Name
 			}
@@ -500,12 +530,12 @@ var alarmCreateCmd = &cobra.Command{
 			comparison := make(map[string]interface{})
 			err := json.Unmarshal([]byte(alarmComparison), &comparison)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 
 			rangetime, err := strconv.Atoi(fmt.Sprintf("%v", comparison["range_time"]))
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			alarmCreateRequest.Comparison = &gobizfly.Comparison{
 				Measurement: comparison["measurement"].(string),
@@ -522,7 +552,7 @@ var alarmCreateCmd = &cobra.Command{
 			for _, volumeID := range alarmVolumes {
 				volume, err := client.CloudServer.Volumes().Get(ctx, volumeID)
 				if err != nil {
-					log.Fatal(err)
+					return err
 				}
 				volumesMonitor = append(volumesMonitor, gobizfly.AlarmVolumesMonitor{
 					ID:   volume.ID,
@@ -537,7 +567,7 @@ var alarmCreateCmd = &cobra.Command{
 			for _, instanceID := range alarmInstances {
 				instance, err := client.CloudServer.Get(ctx, instanceID)
 				if err != nil {
-					log.Fatal(err)
+					return err
 				}
 				instancesMonitor = append(instancesMonitor, gobizfly.AlarmInstancesMonitors{
 					ID:   instance.ID,
@@ -549,23 +579,23 @@ var alarmCreateCmd = &cobra.Command{
 
 		response, err := client.CloudWatcher.Alarms().Create(ctx, &alarmCreateRequest)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		alarm, _ := client.CloudWatcher.Alarms().Get(ctx, response.ID)
 
 		var jsonAlarmData = make(map[string]interface{})
 		byteData, err := json.Marshal(alarm)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonAlarmData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonAlarmData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, alarm)
 	},
 }
 
@@ -573,29 +603,32 @@ var alarmShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show detail alarm",
 	Long:  "Show detail alarm by alarm ID",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		alarm, err := client.CloudWatcher.Alarms().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var jsonAlarmData = make(map[string]interface{})
 		byteData, err := json.Marshal(alarm)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonAlarmData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonAlarmData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, alarm)
 	},
 }
 
@@ -603,17 +636,21 @@ var alarmDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete an alarm",
 	Long:  "Delete an alarm by alarm ID",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.CloudWatcher.Alarms().Delete(ctx, args[0])
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = client.CloudWatcher.Alarms().Delete(ctx, args[0])
+		if err != nil {
+			return err
 		}
 
-		log.Printf("Doing delete alarm with ID: %v", args[0])
+		logging.Infof("Doing delete alarm with ID: %v", args[0])
+		return nil
 	},
 }
 
@@ -621,14 +658,17 @@ var alarmSetCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Update an alarm",
 	Long:  "Update an alarm",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %v", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %v", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		oldAlarm, err := client.CloudWatcher.Alarms().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var alarmCreateReceivers = []gobizfly.AlarmReceiversUse{}
@@ -642,10 +682,10 @@ var alarmSetCmd = &cobra.Command{
 					z := strings.Split(parentValue, "=")
 					// Validate data from input
 					if z[0] == "" {
-						log.Fatal("Not found keyword for: ", z[1])
+						return usageError("Not found keyword for: %s", z[1])
 					}
 					if len(z[1]) == 0 {
-						log.Fatal("Have error value for: ", z[0])
+						return usageError("Have error value for: %s", z[0])
 					}
 
 					if strings.Contains(z[1], ",") {
@@ -655,10 +695,10 @@ var alarmSetCmd = &cobra.Command{
 					}
 				}
 				if _, ok := rawReceiver["id"]; !ok {
-					log.Fatal("id of receiver is required")
+					return usageError("id of receiver is required")
 				}
 				if _, ok := rawReceiver["methods"]; !ok {
-					log.Fatal("methods of receiver is required")
+					return usageError("methods of receiver is required")
 				}
 				rawReceivers = append(rawReceivers, rawReceiver)
 
@@ -668,7 +708,7 @@ var alarmSetCmd = &cobra.Command{
 			for _, rawReceiver := range rawReceivers {
 				receiver, err := client.CloudWatcher.Receivers().Get(ctx, rawReceiver["id"].(string))
 				if err != nil {
-					log.Fatal(err)
+					return err
 				}
 
 				var acr = gobizfly.AlarmReceiversUse{
@@ -676,23 +716,33 @@ var alarmSetCmd = &cobra.Command{
 					ReceiverID: receiver.ReceiverID,
 				}
 				if _, ok := SliceContains(rawReceiver["methods"], "telegram"); ok {
-					MethodsReceiverIsNull(receiver.ReceiverID, "telegram", receiver.TelegramChatID)
+					if err := MethodsReceiverIsNull(receiver.ReceiverID, "telegram", receiver.TelegramChatID); err != nil {
+						return err
+					}
 					acr.TelegramChatID = receiver.TelegramChatID
 				}
 				if _, ok := SliceContains(rawReceiver["methods"], "email"); ok {
-					MethodsReceiverIsNull(receiver.ReceiverID, "email", receiver.EmailAddress)
+					if err := MethodsReceiverIsNull(receiver.ReceiverID, "email", receiver.EmailAddress); err != nil {
+						return err
+					}
 					acr.EmailAddress = receiver.EmailAddress
 				}
 				if _, ok := SliceContains(rawReceiver["methods"], "webhook_url"); ok {
-					MethodsReceiverIsNull(receiver.ReceiverID, "webhook_url", receiver.WebhookURL)
+					if err := MethodsReceiverIsNull(receiver.ReceiverID, "webhook_url", receiver.WebhookURL); err != nil {
+						return err
+					}
 					acr.WebhookURL = receiver.WebhookURL
 				}
 				if _, ok := SliceContains(rawReceiver["methods"], "slack"); ok {
-					MethodsReceiverIsNull(receiver.ReceiverID, "slack", receiver.Slack.SlackChannelName)
+					if err := MethodsReceiverIsNull(receiver.ReceiverID, "slack", receiver.Slack.SlackChannelName); err != nil {
+						return err
+					}
 					acr.SlackChannelName = receiver.Slack.SlackChannelName
 				}
 				if _, ok := SliceContains(rawReceiver["methods"], "sms"); ok {
-					MethodsReceiverIsNull(receiver.ReceiverID, "sms", receiver.SMSNumber)
+					if err := MethodsReceiverIsNull(receiver.ReceiverID, "sms", receiver.SMSNumber); err != nil {
+						return err
+					}
 					acr.SMSNumber = receiver.SMSNumber
 					elem, ok := rawReceiver["sms_interval"]
 					if ok {
@@ -734,7 +784,7 @@ var alarmSetCmd = &cobra.Command{
 		var alarmLoadBalancersMonitors = []*gobizfly.AlarmLoadBalancersMonitor{}
 		if len(alarmLoadBalancers) > 0 {
 			if len(alarmLoadBalancers) > 1 {
-				log.Fatal("UNSUPPORTED multiple load balancers")
+				return usageError("UNSUPPORTED multiple load balancers")
 			}
 			var rawLoadBalancers = []map[string]interface{}{}
 			for _, alarmLoadBalancer := range alarmLoadBalancers {
@@ -744,10 +794,10 @@ var alarmSetCmd = &cobra.Command{
 					z := strings.Split(parentValue, "=")
 					// Validate data from input
 					if z[0] == "" {
-						log.Fatal("Not found keyword for: ", z[1])
+						return usageError("Not found keyword for: %s", z[1])
 					}
 					if len(z[1]) == 0 {
-						log.Fatal("Have error value for: ", z[0])
+						return usageError("Have error value for: %s", z[0])
 					}
 
 					if strings.Contains(z[1], ",") {
@@ -757,16 +807,16 @@ var alarmSetCmd = &cobra.Command{
 					}
 				}
 				if _, ok := rawLoadBalancer["id"]; !ok {
-					log.Fatal("id of load balancer is required")
+					return usageError("id of load balancer is required")
 				}
 				if _, ok := rawLoadBalancer["tgid"]; !ok {
-					log.Fatal("id of backend/frontend of load balancer is required")
+					return usageError("id of backend/frontend of load balancer is required")
 				}
 				if _, ok := rawLoadBalancer["tgtype"]; !ok {
-					log.Fatal("type of tgid is required")
+					return usageError("type of tgid is required")
 				}
 				if _, ok := SliceContains(alarmLoadBalancersTarget, rawLoadBalancer["tgtype"]); !ok {
-					log.Fatal("type of tgid is unsupported")
+					return usageError("type of tgid is unsupported")
 				}
 				rawLoadBalancers = append(rawLoadBalancers, rawLoadBalancer)
 
@@ -774,7 +824,7 @@ var alarmSetCmd = &cobra.Command{
 			for _, rawLoadBalancer := range rawLoadBalancers {
 				lb, err := client.CloudLoadBalancer.Get(ctx, rawLoadBalancer["id"].(string))
 				if err != nil {
-					log.Fatal(err)
+					return err
 				}
 
 				var albm = gobizfly.AlarmLoadBalancersMonitor{
@@ -788,13 +838,13 @@ var alarmSetCmd = &cobra.Command{
 				if rawLoadBalancer["tgtype"] == "frontend" {
 					frontend, err := client.CloudLoadBalancer.Listeners().Get(ctx, rawLoadBalancer["tgid"].(string))
 					if err != nil {
-						log.Fatal(err)
+						return err
 					}
 					albm.TargetName = frontend.Name
 				} else {
 					backend, err := client.CloudLoadBalancer.Pools().Get(ctx, rawLoadBalancer["tgid"].(string))
 					if err != nil {
-						log.Fatal(err)
+						return err
 					}
 					albm.TargetName = backend.Name
 				}
@@ -822,12 +872,12 @@ var alarmSetCmd = &cobra.Command{
 			comparison := make(map[string]interface{})
 			err := json.Unmarshal([]byte(alarmComparison), &comparison)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 
 			rangetime, err := strconv.Atoi(fmt.Sprintf("%v", comparison["range_time"]))
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			alarmUpdateRequest.Comparison = &gobizfly.Comparison{
 				Measurement: comparison["measurement"].(string),
@@ -844,7 +894,7 @@ var alarmSetCmd = &cobra.Command{
 			for _, volumeID := range alarmVolumes {
 				volume, err := client.CloudServer.Volumes().Get(ctx, volumeID)
 				if err != nil {
-					log.Fatal(err)
+					return err
 				}
 				volumesMonitor = append(volumesMonitor, gobizfly.AlarmVolumesMonitor{
 					ID:   volume.ID,
@@ -863,7 +913,7 @@ var alarmSetCmd = &cobra.Command{
 			for _, instanceID := range alarmInstances {
 				instance, err := client.CloudServer.Get(ctx, instanceID)
 				if err != nil {
-					log.Fatal(err)
+					return err
 				}
 				instancesMonitor = append(instancesMonitor, gobizfly.AlarmInstancesMonitors{
 					ID:   instance.ID,
@@ -879,23 +929,23 @@ var alarmSetCmd = &cobra.Command{
 
 		response, err := client.CloudWatcher.Alarms().Update(ctx, args[0], &alarmUpdateRequest)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		alarm, _ := client.CloudWatcher.Alarms().Get(ctx, response.ID)
 
 		var jsonAlarmData = make(map[string]interface{})
 		byteData, err := json.Marshal(alarm)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonAlarmData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonAlarmData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, alarm)
 	},
 }
 
@@ -903,33 +953,36 @@ var alarmEnableCmd = &cobra.Command{
 	Use:   "enable",
 	Short: "Enable an alarm",
 	Long:  "Enable an alarm",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %v", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %v", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		alarmUpdateRequest := gobizfly.AlarmUpdateRequest{
 			Enable: true,
 		}
 		response, err := client.CloudWatcher.Alarms().Update(ctx, args[0], &alarmUpdateRequest)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		alarm, _ := client.CloudWatcher.Alarms().Get(ctx, response.ID)
 
 		var jsonAlarmData = make(map[string]interface{})
 		byteData, err := json.Marshal(alarm)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonAlarmData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonAlarmData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, alarm)
 	},
 }
 
@@ -937,33 +990,36 @@ var alarmDisableCmd = &cobra.Command{
 	Use:   "disable",
 	Short: "Disable an alarm",
 	Long:  "Disable an alarm",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %v", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %v", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		alarmUpdateRequest := gobizfly.AlarmUpdateRequest{
 			Enable: false,
 		}
 		response, err := client.CloudWatcher.Alarms().Update(ctx, args[0], &alarmUpdateRequest)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		alarm, _ := client.CloudWatcher.Alarms().Get(ctx, response.ID)
 
 		var jsonAlarmData = make(map[string]interface{})
 		byteData, err := json.Marshal(alarm)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonAlarmData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonAlarmData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, alarm)
 	},
 }
 
@@ -975,8 +1031,9 @@ var receiverCmd = &cobra.Command{
 	Use:   "receiver",
 	Short: "Bizfly Cloud Watcher Interaction with receiver resources",
 	Long:  `Interact with Cloud Watcher Service. Allow do CRUD alarms, receivers, ...`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Interacting with cloud watcher service")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logging.Verbosef("Interacting with cloud watcher service")
+		return nil
 	},
 }
 
@@ -984,11 +1041,14 @@ var receiverListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List receivers",
 	Long:  "List receivers in your account",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		receivers, err := client.CloudWatcher.Receivers().List(ctx, nil)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
@@ -996,7 +1056,7 @@ var receiverListCmd = &cobra.Command{
 			s := table.Row{receiver.ReceiverID, receiver.Name}
 			data = append(data, s)
 		}
-		formatter.SimpleOutput(receiverListHeader, data)
+		return formatter.SimpleOutput(receiverListHeader, data, receivers)
 	},
 }
 
@@ -1004,9 +1064,12 @@ var receiverCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create an receiver",
 	Long:  "Create an receiver by specific informations",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var rcr = gobizfly.ReceiverCreateRequest{}
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 
 		// current not need handle
 		// if len(receiverSlack) > 0 {
@@ -1019,23 +1082,23 @@ var receiverCreateCmd = &cobra.Command{
 				z := strings.Split(parentValue, "=")
 				// Validate data from input
 				if z[0] == "" {
-					log.Fatal("Not found keyword for: ", z[1])
+					return usageError("Not found keyword for: %s", z[1])
 				}
 				if len(z[1]) == 0 {
-					log.Fatal("Have error value for: ", z[0])
+					return usageError("Have error value for: %s", z[0])
 				}
 
 				rawAutoScaling[z[0]] = z[1]
 			}
 			if _, ok := rawAutoScaling["type"]; !ok {
-				log.Fatal("action type is required for auto scaling group")
+				return usageError("action type is required for auto scaling group")
 			}
 			if _, ok := rawAutoScaling["id"]; !ok {
-				log.Fatal("id of for auto scaling group is required")
+				return usageError("id of for auto scaling group is required")
 			}
 			webhook, err := client.AutoScaling.Webhooks().Get(ctx, rawAutoScaling["id"], rawAutoScaling["type"])
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			rcr.AutoScale = webhook
 		}
@@ -1058,27 +1121,27 @@ var receiverCreateCmd = &cobra.Command{
 
 		response, err := client.CloudWatcher.Receivers().Create(ctx, &rcr)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		receiver, err := client.CloudWatcher.Receivers().Get(ctx, response.ID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var jsonReceiverData = make(map[string]interface{})
 		byteData, err := json.Marshal(receiver)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonReceiverData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonReceiverData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, receiver)
 	},
 }
 
@@ -1086,20 +1149,24 @@ var receiverGetVerificationLinkCmd = &cobra.Command{
 	Use:   "verify",
 	Short: "Get a link verify a method of receiver",
 	Long:  "Get a link verify a method of receiver by specific informations",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %v", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %v", strings.Join(args[1:], ""))
 		}
 		if _, ok := SliceContains(receiverMethodSupportVerify, receiverType); !ok {
-			log.Fatalf("Method %v is unsupported to get link verification", receiverType)
+			return usageError("Method %v is unsupported to get link verification", receiverType)
 		}
 
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if err := client.CloudWatcher.Receivers().ResendVerificationLink(ctx, args[0], receiverType); err == nil {
-			log.Printf("A link verification was sent to %v of receiver %v", receiverType, args[0])
+			logging.Infof("A link verification was sent to %v of receiver %v", receiverType, args[0])
 		} else {
-			log.Fatalf("Failed to sent link verification to %v of receiver %v", receiverType, args[0])
+			return fmt.Errorf("Failed to sent link verification to %v of receiver %v", receiverType, args[0])
 		}
+		return nil
 	},
 }
 
@@ -1107,29 +1174,32 @@ var receiverShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show detail receiver",
 	Long:  "Show detail receiver by receiver ID",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		receiver, err := client.CloudWatcher.Receivers().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var jsonReceiverData = make(map[string]interface{})
 		byteData, err := json.Marshal(receiver)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonReceiverData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonReceiverData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, receiver)
 	},
 }
 
@@ -1137,17 +1207,21 @@ var receiverDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a receiver",
 	Long:  "Delete a receiver by receiver ID",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.CloudWatcher.Receivers().Delete(ctx, args[0])
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		err = client.CloudWatcher.Receivers().Delete(ctx, args[0])
+		if err != nil {
+			return err
 		}
 
-		log.Printf("Doing delete receiver with ID: %v", args[0])
+		logging.Infof("Doing delete receiver with ID: %v", args[0])
+		return nil
 	},
 }
 
@@ -1155,15 +1229,18 @@ var receiverSetCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Update an receiver",
 	Long:  "Update an receiver by specific informations",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %v", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %v", strings.Join(args[1:], ""))
 		}
 
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		oldReceiver, err := client.CloudWatcher.Receivers().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var rcr = gobizfly.ReceiverCreateRequest{}
@@ -1178,23 +1255,23 @@ var receiverSetCmd = &cobra.Command{
 				z := strings.Split(parentValue, "=")
 				// Validate data from input
 				if z[0] == "" {
-					log.Fatal("Not found keyword for: ", z[1])
+					return usageError("Not found keyword for: %s", z[1])
 				}
 				if len(z[1]) == 0 {
-					log.Fatal("Have error value for: ", z[0])
+					return usageError("Have error value for: %s", z[0])
 				}
 
 				rawAutoScaling[z[0]] = z[1]
 			}
 			if _, ok := rawAutoScaling["type"]; !ok {
-				log.Fatal("action type is required for auto scaling group")
+				return usageError("action type is required for auto scaling group")
 			}
 			if _, ok := rawAutoScaling["id"]; !ok {
-				log.Fatal("id of for auto scaling group is required")
+				return usageError("id of for auto scaling group is required")
 			}
 			webhook, err := client.AutoScaling.Webhooks().Get(ctx, rawAutoScaling["id"], rawAutoScaling["type"])
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			rcr.AutoScale = webhook
 		} else {
@@ -1229,27 +1306,27 @@ var receiverSetCmd = &cobra.Command{
 
 		response, err := client.CloudWatcher.Receivers().Update(ctx, args[0], &rcr)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		receiver, err := client.CloudWatcher.Receivers().Get(ctx, response.ID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var jsonReceiverData = make(map[string]interface{})
 		byteData, err := json.Marshal(receiver)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonReceiverData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonReceiverData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, receiver)
 	},
 }
 
@@ -1257,15 +1334,18 @@ var receiverUnSetCmd = &cobra.Command{
 	Use:   "unset",
 	Short: "Remove a method receiver",
 	Long:  "Remove a method receiver by specific informations",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %v", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %v", strings.Join(args[1:], ""))
 		}
 
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		oldReceiver, err := client.CloudWatcher.Receivers().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var rcr = gobizfly.ReceiverCreateRequest{
@@ -1302,27 +1382,27 @@ var receiverUnSetCmd = &cobra.Command{
 
 		response, err := client.CloudWatcher.Receivers().Update(ctx, args[0], &rcr)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		receiver, err := client.CloudWatcher.Receivers().Get(ctx, response.ID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var jsonReceiverData = make(map[string]interface{})
 		byteData, err := json.Marshal(receiver)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonReceiverData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonReceiverData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, receiver)
 	},
 }
 
@@ -1334,8 +1414,9 @@ var historyCmd = &cobra.Command{
 	Use:   "history",
 	Short: "Bizfly Cloud Watcher Interaction with history resources",
 	Long:  `Interact with Cloud Watcher Service. Allow do CRUD alarms, receivers, ...`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Interacting with cloud watcher service")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logging.Verbosef("Interacting with cloud watcher service")
+		return nil
 	},
 }
 
@@ -1343,11 +1424,14 @@ var historyListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List history",
 	Long:  "List 26 latest history in your account",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		histories, err := client.CloudWatcher.Histories().List(ctx, nil)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
@@ -1362,7 +1446,7 @@ var historyListCmd = &cobra.Command{
 			}
 			data = append(data, s)
 		}
-		formatter.SimpleOutput(historyListHeader, data)
+		return formatter.SimpleOutput(historyListHeader, data, histories)
 	},
 }
 
@@ -1374,8 +1458,9 @@ var secretCmd = &cobra.Command{
 	Use:   "secret",
 	Short: "Bizfly Cloud Watcher Interaction with secret resources",
 	Long:  `Interact with Cloud Watcher Service. Allow do CRUD alarms, secrets, ...`,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Interacting with cloud watcher service")
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logging.Verbosef("Interacting with cloud watcher service")
+		return nil
 	},
 }
 
@@ -1383,11 +1468,14 @@ var secretListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List secrets",
 	Long:  "List secrets in your account",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		secrets, err := client.CloudWatcher.Secrets().List(ctx, nil)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
@@ -1395,7 +1483,7 @@ var secretListCmd = &cobra.Command{
 			s := table.Row{secret.ID, secret.Name}
 			data = append(data, s)
 		}
-		formatter.SimpleOutput(secretListHeader, data)
+		return formatter.SimpleOutput(secretListHeader, data, secrets)
 	},
 }
 
@@ -1403,9 +1491,12 @@ var secretCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create an secret",
 	Long:  "Create an secret by specific informations",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var scr = gobizfly.SecretsCreateRequest{}
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 
 		if len(secretName) > 0 {
 			scr.Name = secretName
@@ -1413,27 +1504,27 @@ var secretCreateCmd = &cobra.Command{
 
 		response, err := client.CloudWatcher.Secrets().Create(ctx, &scr)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		receiver, err := client.CloudWatcher.Secrets().Get(ctx, response.ID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var jsonSecretData = make(map[string]interface{})
 		byteData, err := json.Marshal(receiver)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonSecretData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonSecretData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, receiver)
 	},
 }
 
@@ -1441,29 +1532,32 @@ var secretShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show detail secret",
 	Long:  "Show detail secret by secret ID",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		secret, err := client.CloudWatcher.Secrets().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var jsonSecretData = make(map[string]interface{})
 		byteData, err := json.Marshal(secret)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		err = json.Unmarshal(byteData, &jsonSecretData)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data []table.Row
 		data = ProcessDataTables(data, jsonSecretData)
-		formatter.SimpleOutput(resourceGetHeader, data)
+		return formatter.SimpleOutput(resourceGetHeader, data, secret)
 	},
 }
 
@@ -1471,16 +1565,20 @@ var secretDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a secret",
 	Long:  "Delete a secret by secret ID",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.CloudWatcher.Secrets().Delete(ctx, args[0])
+		err = client.CloudWatcher.Secrets().Delete(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
-		log.Printf("Doing delete secret with ID: %v", args[0])
+		logging.Infof("Doing delete secret with ID: %v", args[0])
+		return nil
 	},
 }
@@ -16,11 +16,10 @@ limitations under the License.
 
 package cmd
 
-import "log"
-
-// MethodsReceiverIsNull - cast error if methods is null
-func MethodsReceiverIsNull(receiverID, methodName string, methodValue interface{}) {
+// MethodsReceiverIsNull - return an error if methods is null
+func MethodsReceiverIsNull(receiverID, methodName string, methodValue interface{}) error {
 	if methodValue.(string) == "" {
-		log.Fatalf("Receiver %v haven't method: %v", receiverID, methodName)
+		return usageError("Receiver %v haven't method: %v", receiverID, methodName)
 	}
+	return nil
 }
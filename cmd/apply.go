@@ -0,0 +1,128 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manifestFiles []string
+	applyHeader   = []string{"Kind", "Name", "ID", "Result"}
+)
+
+// applyResult is what apply did for a resource
+type applyResult struct {
+	Kind    string        `json:"kind" yaml:"kind"`
+	Name    string        `json:"name" yaml:"name"`
+	ID      string        `json:"id" yaml:"id"`
+	Result  string        `json:"result" yaml:"result"`
+	Changes []fieldChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// applyCmd represents the apply command
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Create or update the resources of a manifest",
+	Long: `Create the resources of a manifest which do not exist and update the ones which differ from it.
+The resources are matched by name, or by their labels for the kinds with tags, and are applied in
+the order of the manifest. Resources which are not in the manifest are never deleted.
+Use: bizfly apply -f <manifest.yaml>
+
+Example manifest:
+
+  kind: VPC
+  metadata:
+    name: staging
+  spec:
+    cidr: 10.20.0.0/16
+  ---
+  kind: Volume
+  metadata:
+    name: staging-data
+  spec:
+    size: 50
+    type: PREMIUM-HDD1
+    server: staging-web`,
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		resources, err := readManifests(manifestFiles)
+		if err != nil {
+			return err
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		var results []applyResult
+		var data [][]string
+		defer func() {
+			// the resources applied until an error are still listed
+			if outErr := formatter.Output(applyHeader, data, results); err == nil {
+				err = outErr
+			}
+		}()
+		for _, res := range resources {
+			plan, err := planManifest(ctx, client, res)
+			if err != nil {
+				return err
+			}
+			kind := manifestKinds[res.Kind]
+			result := applyResult{Kind: res.Kind, Name: res.Metadata.Name, Changes: plan.changes}
+			switch plan.action {
+			case actionCreate:
+				if result.ID, err = kind.create(ctx, client, res); err != nil {
+					return fmt.Errorf("%s: create %s: %w", res.source, res, err)
+				}
+				logging.Infof("%s created", res)
+				result.Result = "created"
+			case actionUpdate:
+				result.ID = plan.live.id
+				for _, change := range plan.changes {
+					if !change.Updatable {
+						logging.Warnf("%s: %s can not be changed in place (%v -> %v), recreate the resource to change it",
+							res, change.Field, change.From, change.To)
+					}
+				}
+				result.Result = "unchanged"
+				if changes := plan.updatable(); len(changes) > 0 {
+					if err := kind.update(ctx, client, res, plan.live, changes); err != nil {
+						return fmt.Errorf("%s: update %s: %w", res.source, res, err)
+					}
+					logging.Infof("%s updated", res)
+					result.Result = "updated"
+				}
+			default:
+				result.ID = plan.live.id
+				result.Result = "unchanged"
+			}
+			results = append(results, result)
+			data = append(data, []string{result.Kind, result.Name, result.ID, result.Result})
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringArrayVarP(&manifestFiles, "filename", "f", []string{}, "Manifest file, - for the standard input. Can be repeated")
+	_ = applyCmd.MarkFlagRequired("filename")
+	addWaitFlags(applyCmd)
+}
@@ -0,0 +1,228 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
+	"github.com/spf13/cobra"
+)
+
+// Authentication methods, in the order getApiClient uses them
+const (
+	authMethodToken         = "token"
+	authMethodAppCredential = "app_credential"
+	authMethodPassword      = "password"
+)
+
+var authStatusHeader = []string{"Profile", "Method", "User", "Project ID", "Region", "Token Expires At"}
+
+// authStatus is the output of auth status
+type authStatus struct {
+	Profile        string `json:"profile" yaml:"profile"`
+	Method         string `json:"method" yaml:"method"`
+	User           string `json:"user" yaml:"user"`
+	ProjectID      string `json:"project_id" yaml:"project_id"`
+	Region         string `json:"region" yaml:"region"`
+	TokenExpiresAt string `json:"token_expires_at" yaml:"token_expires_at"`
+}
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect the authentication",
+	Long:  "Inspect the authentication of the active profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help() // Display the help message
+	},
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the authentication of the active profile",
+	Long: `Authenticate with the credentials of the active profile and show the user, project, region,
+expiry of the token and the method in use: the token saved by login, an application credential
+or a password. Exit with 4 when the credentials are missing, rejected or the token has expired.
+Use: bizfly auth status`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		method, err := activeAuthMethod()
+		if err != nil {
+			return err
+		}
+		if method == "" {
+			return authError("Profile %s has no credentials. Use: bizfly login", getActiveProfile())
+		}
+		_, _, tok, err := getApiClientToken(cmd)
+		if err != nil {
+			return err
+		}
+		claims := tokenClaims(tok.KeystoneToken)
+		status := authStatus{
+			Profile:   getActiveProfile(),
+			Method:    method,
+			User:      claimString(claims, "email", "username", "name", "sub"),
+			ProjectID: project_id,
+			Region:    getRegionName(region),
+		}
+		switch method {
+		case authMethodAppCredential:
+			status.User = appCredID
+		case authMethodPassword:
+			status.User = email
+		}
+		if status.ProjectID == "" {
+			status.ProjectID = claimString(claims, "project_id", "tenant_id")
+		}
+		expiresAt, known := tokenExpiresAt(tok.ExpiresAt, claims)
+		if known {
+			status.TokenExpiresAt = expiresAt.Local().Format(time.RFC3339)
+		}
+		data := [][]string{{status.Profile, status.Method, valueOrDash(status.User), valueOrDash(status.ProjectID),
+			status.Region, valueOrDash(status.TokenExpiresAt)}}
+		if err := formatter.Output(authStatusHeader, data, status); err != nil {
+			return err
+		}
+		if known && time.Now().After(expiresAt) {
+			return authError("The token expired at %s. Use: bizfly login", status.TokenExpiresAt)
+		}
+		return nil
+	},
+}
+
+// logoutCmd represents the logout command
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the token of the active profile",
+	Long: `Remove the token saved by login and the cached tokens of the active profile.
+The credentials of the profile, such as an application credential, are kept.
+Use: bizfly logout`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profile := getActiveProfile()
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		removed, err := unsetProfileSecret(cf, profile, "auth_token")
+		if err != nil {
+			return err
+		}
+		if removed {
+			if err := cf.Save(); err != nil {
+				return err
+			}
+		}
+		if err := removeProfileTokenCache(profile); err != nil {
+			return err
+		}
+		if removed {
+			logging.Infof("Logged out of profile %s", profile)
+		} else {
+			logging.Infof("Profile %s has no token saved by login, removed the cached tokens", profile)
+		}
+		if os.Getenv("BIZFLY_CLOUD_AUTH_TOKEN") != "" {
+			logging.Warnf("BIZFLY_CLOUD_AUTH_TOKEN is set and is still used, unset it to log out")
+		}
+		if configValue("app_credential_id") != "" || configValue("email") != "" {
+			logging.Infof("The credentials of profile %s are kept. Use: bizfly config unset <key>", profile)
+		}
+		return nil
+	},
+}
+
+// activeAuthMethod returns the authentication method getApiClient uses, empty
+// when there are no credentials
+func activeAuthMethod() (string, error) {
+	token, err := secretValue("auth_token")
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		return authMethodToken, nil
+	}
+	if appCredID != "" || appCredSecret != "" || configValue("app_credential_id") != "" {
+		return authMethodAppCredential, nil
+	}
+	secret, err := secretValue("app_credential_secret")
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case secret != "":
+		return authMethodAppCredential, nil
+	case email != "" || configValue("email") != "":
+		return authMethodPassword, nil
+	}
+	return "", nil
+}
+
+// tokenClaims returns the claims of a JWT token, without verifying it. Other
+// tokens have no claims.
+func tokenClaims(token string) map[string]interface{} {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil
+	}
+	return claims
+}
+
+// claimString returns the first of the claims which is a non empty string
+func claimString(claims map[string]interface{}, names ...string) string {
+	for _, name := range names {
+		if s, ok := claims[name].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// tokenExpiresAt returns the expiry returned with the token, or the one of its claims
+func tokenExpiresAt(expiresAt string, claims map[string]interface{}) (time.Time, bool) {
+	if t, ok := parseTokenExpiry(expiresAt); ok {
+		return t, true
+	}
+	if exp, ok := claims["exp"].(float64); ok {
+		return time.Unix(int64(exp), 0), true
+	}
+	return time.Time{}, false
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authStatusCmd)
+	rootCmd.AddCommand(logoutCmd)
+}
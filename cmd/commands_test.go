@@ -0,0 +1,208 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCommandEnv makes the test binary run bizfly instead of the tests, so
+// that each command runs in a fresh process with its real exit code
+const runCommandEnv = "BIZFLY_TEST_RUN_COMMAND"
+
+var update = flag.Bool("update", false, "update the golden files of testdata/golden")
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runCommandEnv) == "1" {
+		Execute()
+		os.Exit(0)
+	}
+	flag.Parse()
+	os.Exit(m.Run())
+}
+
+// commandResult is what a command printed and its exit code
+type commandResult struct {
+	stdout, stderr string
+	exitCode       int
+}
+
+// runCommand runs bizfly with args against the fake API, with an empty home
+// and cache directory and credentials from the environment
+func runCommand(t *testing.T, api *fakeAPI, args ...string) commandResult {
+	t.Helper()
+	home := t.TempDir()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = []string{
+		runCommandEnv + "=1",
+		"HOME=" + home,
+		"XDG_CACHE_HOME=" + filepath.Join(home, ".cache"),
+		"NO_COLOR=1",
+		"TZ=UTC",
+		"BIZFLY_CLOUD_API_URL=" + api.URL(),
+		"BIZFLY_CLOUD_EMAIL=test@example.com",
+		"BIZFLY_CLOUD_PASSWORD=test-password",
+		"BIZFLY_CLOUD_PROJECT_ID=fake-project",
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader("")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	result := commandResult{}
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("run %v: %v", args, err)
+		}
+		result.exitCode = exitErr.ExitCode()
+	}
+	result.stdout = strings.ReplaceAll(stdout.String(), api.URL(), "{{API}}")
+	result.stderr = strings.ReplaceAll(stderr.String(), api.URL(), "{{API}}")
+	return result
+}
+
+// golden renders the result of a command as a golden file
+func golden(args []string, result commandResult, requests []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ bizfly %s\n", strings.Join(args, " "))
+	fmt.Fprintf(&b, "-- exit code: %d --\n", result.exitCode)
+	fmt.Fprintf(&b, "-- stdout --\n%s", result.stdout)
+	fmt.Fprintf(&b, "-- stderr --\n%s", result.stderr)
+	b.WriteString("-- requests --\n")
+	for _, request := range requests {
+		b.WriteString(request + "\n")
+	}
+	return b.String()
+}
+
+// checkGolden compares got with testdata/golden/<name>.golden, or writes it with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, run go test ./cmd -update to create it", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s, run go test ./cmd -update if the change is expected\n--- want\n%s\n--- got\n%s",
+			path, want, got)
+	}
+}
+
+// missingID is a server ID without a fixture
+const missingID = "00000000-0000-4000-8000-000000000000"
+
+var commandTests = []struct {
+	name string
+	args []string
+	// failures are the routes of the fake API which fail
+	failures map[string]int
+}{
+	{name: "server-list", args: []string{"server", "list"}},
+	{name: "server-list-wide", args: []string{"server", "list", "-o", "wide"}},
+	{name: "server-list-csv", args: []string{"server", "list", "-o", "csv"}},
+	{name: "server-list-filter", args: []string{"server", "list", "--status", "active", "--zone", "HN1"}},
+	{name: "server-list-sort-limit", args: []string{"server", "list", "--sort-by", "name", "--limit", "2"}},
+	{name: "server-list-name-regex", args: []string{"server", "list", "--name", "/^web-[0-9]+$/"}},
+	{name: "server-list-invalid-sort", args: []string{"server", "list", "--sort-by", "size"}},
+	{name: "server-get-by-name", args: []string{"server", "get", "web-1"}},
+	{name: "server-get-jsonpath", args: []string{"server", "get", "web-1", "-o", "jsonpath={.ip_addresses.WAN_V4[0].addr}"}},
+	{name: "server-list-jsonpath-range", args: []string{"server", "list", "-o", `jsonpath={range .items[*]}{.id}{"\n"}{end}`}},
+	{name: "server-list-jsonpath-unsupported", args: []string{"server", "list", "-o", "jsonpath={.items[?(@.status>1)]}"}},
+	{name: "server-list-go-template", args: []string{"server", "list", "-o", `go-template={{range .items}}{{.name}} {{.status}}{{"\n"}}{{end}}`}},
+	{name: "server-get-by-prefix", args: []string{"server", "get", "d4e5f6a7"}},
+	{name: "server-get-not-found", args: []string{"server", "get", missingID}},
+	{name: "server-get-no-match", args: []string{"server", "get", "mail"}},
+	{name: "server-get-ambiguous", args: []string{"server", "get", "db"}},
+	{name: "server-get-no-args", args: []string{"server", "get"}},
+	{name: "server-delete-no-terminal", args: []string{"server", "delete", "web-1"}},
+	{name: "server-delete-dry-run", args: []string{"server", "delete", "web-1", "--yes", "--dry-run"}},
+	{name: "server-delete-several-dry-run", args: []string{"server", "delete", "web-1", "9c2e4f6a", "--yes", "--dry-run"}},
+	{name: "server-delete", args: []string{"server", "delete", "web-1", "--yes"}},
+	{name: "server-create-small-disk", args: []string{"server", "create", "--name", "web", "--flavor", "nix.2c_4g",
+		"--image-id", "11111111-1111-4111-8111-111111111111", "--rootdisk-size", "10"}},
+	{name: "server-create-same-names", args: []string{"server", "create", "-f", "testdata/server.yaml", "--name", "web", "--count", "2"}},
+	{name: "server-create-json", args: []string{"server", "create", "-f", "testdata/server.yaml", "-o", "json"}},
+	{name: "server-create-invalid-zone", args: []string{"server", "create", "-f", "testdata/server.yaml", "--availability-zone", "HCM1"}},
+	{name: "server-reboot-no-args", args: []string{"server", "reboot", "--hard"}},
+	{name: "server-reboot-servers-and-selector", args: []string{"server", "reboot", "web-1", "--selector", "role=web"}},
+	{name: "server-reboot-invalid-selector", args: []string{"server", "reboot", "--selector", "=web"}},
+	{name: "server-reboot-hard", args: []string{"server", "reboot", "web-1", "d4e5f6a7", "--hard", "--parallel", "1"}},
+	{name: "server-reboot-selector", args: []string{"server", "reboot", "--selector", "role=web"}},
+	{name: "server-reboot-not-found", args: []string{"server", "reboot", "web-1", "mail"}},
+	{name: "server-reboot-no-match", args: []string{"server", "reboot", "--selector", "role=mail"}},
+	{name: "server-hard-reboot-deprecated", args: []string{"server", "hard", "reboot", "web-1"}},
+	{name: "server-ssh-dry-run", args: []string{"server", "ssh", "web-1", "--dry-run"}},
+	{name: "server-ssh-private-jump", args: []string{"server", "ssh", "9c2e4f6a", "--private", "--jump", "web-1", "--dry-run"}},
+	{name: "volume-list", args: []string{"volume", "list"}},
+	{name: "volume-list-wide", args: []string{"volume", "list", "-o", "wide"}},
+	{name: "volume-detach-no-server", args: []string{"volume", "detach", "data-1"}},
+	{name: "loadbalancer-list", args: []string{"loadbalancer", "list"}},
+	{name: "kubernetes-list", args: []string{"kubernetes", "list"}},
+	{name: "dns-list-zones", args: []string{"dns", "list-zones"}},
+	{name: "apply-unchanged", args: []string{"apply", "-f", "testdata/web.yaml"}},
+	{name: "apply-missing-file", args: []string{"apply", "-f", "testdata/missing.yaml"}},
+	{name: "diff-no-changes", args: []string{"diff", "-f", "testdata/web.yaml"}},
+	{name: "diff-changes", args: []string{"diff", "-f", "testdata/web-resized.yaml"}},
+	{name: "config-profile-add", args: []string{"config", "profile", "add", "staging", "--region", "HoChiMinh"}},
+	{name: "config-profile-list", args: []string{"config", "profile", "list"}},
+	{name: "config-profile-use-not-found", args: []string{"config", "profile", "use", "staging"}},
+	{name: "dns-get-zone-no-args", args: []string{"dns", "get-zone"}},
+	{name: "vpc-create-no-name", args: []string{"vpc", "create"}},
+	{name: "firewall-server-list-no-id", args: []string{"firewall", "server", "list"}},
+	{name: "wan-ip-get-no-args", args: []string{"wan-ip", "get"}},
+	{name: "task-get-no-args", args: []string{"task", "get"}},
+	{name: "kafka-resize-invalid-type", args: []string{"kafka", "clusters", "resize", "my-cluster", "--type", "disk"}},
+	{name: "cloudwatcher-alarm-show-extra-args", args: []string{"cloudwatcher", "alarm", "show", "a1", "a2"}},
+	{name: "container-registry-delete-no-args", args: []string{"container-registry", "delete"}},
+	{name: "diff-no-filename", args: []string{"diff"}},
+	{name: "diff-missing-file", args: []string{"diff", "-f", "testdata/missing.yaml"}},
+	{name: "unknown-flag", args: []string{"server", "list", "--no-such-flag"}},
+	{name: "invalid-output", args: []string{"server", "list", "-o", "xml"}},
+	{name: "login-no-browser-no-ticket", args: []string{"login", "--no-browser"}},
+	{name: "login-invalid-port", args: []string{"login", "--port", "70000"}},
+	{name: "auth-status", args: []string{"auth", "status"}},
+	{name: "logout", args: []string{"logout"}},
+	{name: "auth-failed", args: []string{"server", "list"},
+		failures: map[string]int{"POST " + fakeTokenPath: 401}},
+	{name: "api-error", args: []string{"server", "list"},
+		failures: map[string]int{"GET /cloud_server/servers": 500}},
+}
+
+func TestCommands(t *testing.T) {
+	for _, tc := range commandTests {
+		t.Run(tc.name, func(t *testing.T) {
+			api := newFakeAPI(t, tc.failures)
+			result := runCommand(t, api, tc.args...)
+			checkGolden(t, tc.name, golden(tc.args, result, api.Requests()))
+		})
+	}
+}
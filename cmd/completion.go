@@ -0,0 +1,245 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/bizflycloud/gobizfly"
+	"github.com/spf13/cobra"
+)
+
+// completionCacheTTL is how long the resources listed for the completion are reused
+const completionCacheTTL = time.Minute
+
+// flavorKind is only used by the completion, flavors are given by name
+var flavorKind = resourceKind{name: "flavor", list: func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error) {
+	flavors, err := client.CloudServer.Flavors().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs := make([]resourceRef, 0, len(flavors))
+	for _, flavor := range flavors {
+		refs = append(refs, resourceRef{id: flavor.Name})
+	}
+	return refs, nil
+}}
+
+// completionCmd represents the completion command
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate the shell completion script",
+	Long: `Generate the completion script of bizfly for a shell.
+
+Bash:
+  $ source <(bizfly completion bash)
+  # or, for every session on Linux:
+  $ bizfly completion bash > /etc/bash_completion.d/bizfly
+
+Zsh:
+  $ bizfly completion zsh > "${fpath[1]}/_bizfly"
+
+Fish:
+  $ bizfly completion fish > ~/.config/fish/completions/bizfly.fish
+
+PowerShell:
+  PS> bizfly completion powershell | Out-String | Invoke-Expression
+`,
+	DisableFlagsInUseLine: true,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.ExactValidArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out := cmd.OutOrStdout()
+		switch args[0] {
+		case "bash":
+			return cmd.Root().GenBashCompletion(out)
+		case "zsh":
+			return cmd.Root().GenZshCompletion(out)
+		case "fish":
+			return cmd.Root().GenFishCompletion(out, true)
+		case "powershell":
+			return cmd.Root().GenPowerShellCompletion(out)
+		}
+		return nil
+	},
+}
+
+// completionCacheEntry is the content of a completion cache file
+type completionCacheEntry struct {
+	FetchedAt time.Time `json:"fetched_at"`
+	Values    []string  `json:"values"`
+}
+
+// completionCachePath returns the cache file of a kind of resources for the
+// active profile and the region of the command, including --region
+func completionCachePath(cmd *cobra.Command, kind resourceKind) string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	name := unsafeFileChars.ReplaceAllString(activeRegionName(cmd)+"_"+kind.name, "_") + ".json"
+	return filepath.Join(dir, "bizfly", "completion", unsafeFileChars.ReplaceAllString(getActiveProfile(), "_"), name)
+}
+
+// completionValues returns the IDs of the resources with their name as the
+// description, from the cache when it is recent enough
+func completionValues(cmd *cobra.Command, kind resourceKind) []string {
+	path := completionCachePath(cmd, kind)
+	if path != "" {
+		if b, err := os.ReadFile(path); err == nil {
+			var entry completionCacheEntry
+			if json.Unmarshal(b, &entry) == nil && time.Since(entry.FetchedAt) < completionCacheTTL {
+				return entry.Values
+			}
+		}
+	}
+	client, ctx, err := getApiClient(cmd)
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil
+	}
+	refs, err := kind.list(ctx, client)
+	if err != nil {
+		cobra.CompErrorln(err.Error())
+		return nil
+	}
+	values := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		if len(ref.names) > 0 && ref.names[0] != "" {
+			values = append(values, ref.id+"\t"+ref.names[0])
+		} else {
+			values = append(values, ref.id)
+		}
+	}
+	if path != "" {
+		if b, err := json.Marshal(completionCacheEntry{FetchedAt: time.Now(), Values: values}); err == nil {
+			if os.MkdirAll(filepath.Dir(path), 0700) == nil {
+				_ = os.WriteFile(path, b, 0600)
+			}
+		}
+	}
+	return values
+}
+
+func filterCompletions(values []string, toComplete string) []string {
+	var result []string
+	for _, value := range values {
+		if strings.HasPrefix(value, toComplete) {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// completeArgs completes each positional argument with the resources of the
+// kind at the same position
+func completeArgs(kinds ...resourceKind) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) >= len(kinds) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return filterCompletions(completionValues(cmd, kinds[len(args)]), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeEachArg completes every positional argument with the resources of
+// a kind, for commands taking a list of IDs
+func completeEachArg(kind resourceKind) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return filterCompletions(completionValues(cmd, kind), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completeFlag completes the value of a flag with the resources of a kind
+func completeFlag(cmd *cobra.Command, flag string, kind resourceKind) {
+	_ = cmd.RegisterFlagCompletionFunc(flag, completeEachArg(kind))
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+}
+
+// registerCompletions adds the dynamic completion of the arguments and flags.
+// It runs from Execute, once the init functions of all commands added their flags.
+func registerCompletions() {
+	serverCmds := []*cobra.Command{
+		serverGetCmd, serverStopCmd, serverStartCmd, serverResizeCmd, serverAddVPCCmd,
+		serverRemoveVPCCmd, serverChangeNetworkPlanCmd, serverSwitchBillingPlanCmd, serverRename, serverSSHCmd,
+	}
+	for _, cmd := range serverCmds {
+		cmd.ValidArgsFunction = completeArgs(serverKind)
+	}
+	serverDeleteCmd.ValidArgsFunction = completeEachArg(serverKind)
+	serverRebootCmd.ValidArgsFunction = completeEachArg(serverKind)
+	completeFlag(serverCreateCmd, "flavor", flavorKind)
+	completeFlag(serverCreateCmd, "volume-id", volumeKind)
+	completeFlag(serverCreateCmd, "firewall", firewallKind)
+	completeFlag(serverCreateCmd, "ssh-key", sshKeyKind)
+	completeFlag(serverResizeCmd, "flavor", flavorKind)
+	completeFlag(serverSSHCmd, "jump", serverKind)
+	completeFlag(serverAddVPCCmd, "vpc-ids", vpcKind)
+	completeFlag(serverRemoveVPCCmd, "vpc-ids", vpcKind)
+
+	for _, cmd := range []*cobra.Command{volumeGetCmd, extendVolumeCmd, restoreVolumeCmd, patchVolumeCmd} {
+		cmd.ValidArgsFunction = completeArgs(volumeKind)
+	}
+	volumeDeleteCmd.ValidArgsFunction = completeEachArg(volumeKind)
+	volumeAttachCmd.ValidArgsFunction = completeArgs(volumeKind, serverKind)
+	volumeDetachCmd.ValidArgsFunction = completeArgs(volumeKind, serverKind)
+	completeFlag(volumeCreateCmd, "server-id", serverKind)
+
+	for _, cmd := range []*cobra.Command{vpcGetCmd, vpcUpdateCmd, vpcDeleteCmd} {
+		cmd.ValidArgsFunction = completeArgs(vpcKind)
+	}
+
+	for _, cmd := range []*cobra.Command{firewallServerList, firewallRuleListCmd, firewallRuleDeleteCmd, firewallRuleCreateCmd} {
+		cmd.ValidArgsFunction = completeArgs(firewallKind)
+	}
+	firewallDeleteCmd.ValidArgsFunction = completeEachArg(firewallKind)
+	firewallServerRemove.ValidArgsFunction = func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		kind := serverKind
+		if len(args) == 0 {
+			kind = firewallKind
+		}
+		return filterCompletions(completionValues(cmd, kind), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	lbGetCmd.ValidArgsFunction = completeArgs(loadBalancerKind)
+	lbResizeLoadBalancerCmd.ValidArgsFunction = completeArgs(loadBalancerKind)
+	lbDeleteCmd.ValidArgsFunction = completeEachArg(loadBalancerKind)
+	completeFlag(lbCreateCmd, "network-id", vpcKind)
+
+	for _, cmd := range []*cobra.Command{
+		clusterGet, clusterDelete, addWorkerPool, deleteWorkerPool, getWorkerPool, updateWorkerPool,
+		recycleNode, deleteWorkerPoolNode, getKubeConfig,
+	} {
+		cmd.ValidArgsFunction = completeArgs(clusterKind)
+	}
+	completeFlag(clusterCreate, "vpc-network-id", vpcKind)
+
+	sshKeyDeleteCmd.ValidArgsFunction = completeArgs(sshKeyKind)
+	completeFlag(wanIPCreateCmd, "server-id", serverKind)
+	completeFlag(wanIpAttachServerCmd, "server-id", serverKind)
+	for _, cmd := range []*cobra.Command{wanIPGetCmd, wanIpDeleteCmd, wanIpAttachServerCmd, wanIpDetachServerCmd, wanIpConvertToPaidCmd} {
+		cmd.ValidArgsFunction = completeArgs(wanIPKind)
+	}
+}
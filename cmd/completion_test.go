@@ -0,0 +1,39 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestCompletionCachePathRegion(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defer func(r string) { region = r }(region)
+	cmd := &cobra.Command{Use: "get"}
+	cmd.Flags().StringVar(&region, "region", "HaNoi", "")
+	hanoi := completionCachePath(cmd, serverKind)
+	if err := cmd.Flags().Set("region", "HoChiMinh"); err != nil {
+		t.Fatal(err)
+	}
+	hcm := completionCachePath(cmd, serverKind)
+	if filepath.Base(hanoi) != "HaNoi_server.json" || filepath.Base(hcm) != "HoChiMinh_server.json" {
+		t.Errorf("got %s and %s, want a file per region", hanoi, hcm)
+	}
+}
@@ -0,0 +1,548 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bizflycloud/bizflyctl/constants"
+	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+var (
+	profileListHeader = []string{"Name", "Current", "Region", "Project ID", "User"}
+	showSecrets       bool
+	migrateStore      string
+)
+
+// profileFlags maps the global flags accepted by "config profile add" to the profile keys
+var profileFlags = map[string]string{
+	"email":                 "email",
+	"password":              "password",
+	"app-credential-id":     "app_credential_id",
+	"app-credential-secret": "app_credential_secret",
+	"region":                "region",
+	"project-id":            "project_id",
+	"api-url":               "api_url",
+	"auth-url":              "auth_url",
+	"ca-cert":               "ca_cert",
+	"insecure-skip-verify":  "insecure_skip_verify",
+}
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage bizflyctl configuration",
+	Long:  "Manage bizflyctl configuration and profiles stored in $HOME/.bizfly.yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help() // Display the help message
+	},
+}
+
+var configProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named profiles",
+	Long: `Manage named profiles. Each profile holds its own credentials, region, project and cached token.
+The active profile is selected by --profile, the BIZFLY_CLOUD_PROFILE environment variable
+or "bizfly config profile use", in this order.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		_ = cmd.Help() // Display the help message
+	},
+}
+
+var configProfileAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add or update a profile",
+	Long: `Add a profile or update its settings with the global credential flags.
+Use: bizfly config profile add <name> [--email <email> --password <password>] [--app-credential-id <id> --app-credential-secret <secret>] [--region <region>] [--project-id <project-id>] [--api-url <url>] [--ca-cert <file>]
+Example: bizfly config profile add staging --region HoChiMinh --project-id 12345678-1234-1234-1234-123456789012
+Example: bizfly config profile add onprem --region VC-HaNoi --api-url https://cloud.example.com --ca-cert /etc/ssl/example-ca.pem`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return usageError("You need to specify the profile name. Use: bizfly config profile add <name>")
+		}
+		name := args[0]
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		created := !cf.HasProfile(name)
+		if created {
+			cf.Set(profilesKey+"."+name, nil)
+		}
+		for flagName, key := range profileFlags {
+			flag := cmd.Flags().Lookup(flagName)
+			if flag == nil || !flag.Changed {
+				continue
+			}
+			value := flag.Value.String()
+			if key == "region" && getRegionName(value) == "" {
+				return usageError("Invalid region %s", value)
+			}
+			if key == "api_url" || key == "auth_url" {
+				if _, err := endpointURL(flagName, value, ""); err != nil {
+					return err
+				}
+			}
+			if _, secret := SliceContains(secretKeys, key); secret {
+				if err := setProfileSecret(cf, name, key, value); err != nil {
+					return err
+				}
+				continue
+			}
+			cf.Set(profilesKey+"."+name+"."+key, value)
+		}
+		if err := cf.Save(); err != nil {
+			return err
+		}
+		// credentials may have changed, drop the tokens cached for the old ones
+		if err := removeProfileTokenCache(name); err != nil {
+			logging.Warnf("failed to remove cached tokens: %v", err)
+		}
+		if created {
+			logging.Infof("Added profile %s", name)
+		} else {
+			logging.Infof("Updated profile %s", name)
+		}
+		return nil
+	},
+}
+
+var configProfileUseCmd = &cobra.Command{
+	Use:   "use",
+	Short: "Set the current profile",
+	Long: `Set the profile used when --profile is not given
+Use: bizfly config profile use <name>`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return usageError("You need to specify the profile name. Use: bizfly config profile use <name>")
+		}
+		name := args[0]
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if name != defaultProfile && !cf.HasProfile(name) {
+			return notFoundError("Profile %s is not found. Use: bizfly config profile add %s", name, name)
+		}
+		cf.Set(currentProfileKey, name)
+		if err := cf.Save(); err != nil {
+			return err
+		}
+		logging.Infof("Switched to profile %s", name)
+		return nil
+	},
+}
+
+var configProfileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		activeProfile := getActiveProfile()
+		names := cf.Profiles()
+		if !cf.HasProfile(defaultProfile) {
+			names = append([]string{defaultProfile}, names...)
+		}
+		type profileInfo struct {
+			Name      string `json:"name"`
+			Current   bool   `json:"current"`
+			Region    string `json:"region"`
+			ProjectID string `json:"project_id"`
+			User      string `json:"user"`
+		}
+		var profiles []profileInfo
+		var data [][]string
+		for _, name := range names {
+			value := func(key string) string {
+				if v, ok := cf.Get(profileKey(name, key)); ok && v != nil {
+					return fmt.Sprint(v)
+				}
+				return ""
+			}
+			user := value("email")
+			if user == "" {
+				user = value("app_credential_id")
+			}
+			current := ""
+			if name == activeProfile {
+				current = "*"
+			}
+			profiles = append(profiles, profileInfo{Name: name, Current: name == activeProfile,
+				Region: value("region"), ProjectID: value("project_id"), User: user})
+			data = append(data, []string{name, current, value("region"), value("project_id"), user})
+		}
+		return formatter.Output(profileListHeader, data, profiles)
+	},
+}
+
+var configProfileDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a profile",
+	Long: `Delete a profile and its cached tokens
+Use: bizfly config profile delete <name>`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return usageError("You need to specify the profile name. Use: bizfly config profile delete <name>")
+		}
+		name := args[0]
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		if !cf.Unset(profilesKey + "." + name) {
+			return notFoundError("Profile %s is not found", name)
+		}
+		if current, ok := cf.Get(currentProfileKey); ok && fmt.Sprint(current) == name {
+			cf.Unset(currentProfileKey)
+		}
+		for _, key := range secretKeys {
+			if _, err := unsetProfileSecret(cf, name, key); err != nil {
+				logging.Warnf("failed to remove %s from the secret store: %v", key, err)
+			}
+		}
+		if err := cf.Save(); err != nil {
+			return err
+		}
+		if err := removeProfileTokenCache(name); err != nil {
+			logging.Warnf("failed to remove cached tokens: %v", err)
+		}
+		logging.Infof("Deleted profile %s", name)
+		return nil
+	},
+}
+
+var configProfileCurrentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Print the active profile",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fmt.Println(getActiveProfile())
+		return nil
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Print a setting of the active profile",
+	Long: `Print a setting of the active profile from the config file. Secrets are redacted unless --show-secrets is given.
+Use: bizfly config get <key>
+Example: bizfly config get region`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return usageError("You need to specify the key. Use: bizfly config get <key>")
+		}
+		key := args[0]
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		path, err := configKeyPath(cf, key)
+		if err != nil {
+			return err
+		}
+		value, ok := cf.Get(path)
+		_, secret := SliceContains(secretKeys, key)
+		if secret {
+			v, err := profileSecret(cf, getActiveProfile(), key)
+			if err != nil {
+				return err
+			}
+			value, ok = v, v != ""
+		}
+		if !ok || value == nil {
+			return notFoundError("%s is not set", key)
+		}
+		if secret && !showSecrets {
+			value = redactedValue
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set a setting of the active profile",
+	Long: fmt.Sprintf(`Set a setting of the active profile in the config file.
+Valid keys: %s
+Use: bizfly config set <key> <value>
+Example: bizfly config set region HoChiMinh`, strings.Join(configKeys(), ", ")),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return usageError("You need to specify the key and the value. Use: bizfly config set <key> <value>")
+		}
+		key, value := args[0], args[1]
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		path, err := configKeyPath(cf, key)
+		if err != nil {
+			return err
+		}
+		switch key {
+		case "region":
+			regionName := getRegionName(value)
+			if regionName == "" {
+				return usageError("Invalid region %s. Valid regions: %s", value, strings.Join(regionNames(), ", "))
+			}
+			value = regionName
+		case "api_url", "auth_url":
+			if _, err := endpointURL(strings.ReplaceAll(key, "_", "-"), value, ""); err != nil {
+				return err
+			}
+		case "insecure_skip_verify":
+			if _, err := strconv.ParseBool(value); err != nil {
+				return usageError("Invalid %s %q, use true or false", key, value)
+			}
+		case "timeout":
+			if d, err := time.ParseDuration(value); err != nil || d < 0 {
+				return usageError("Invalid timeout %q, use a duration such as 30s or 2m", value)
+			}
+		case currentProfileKey:
+			if value != defaultProfile && !cf.HasProfile(value) {
+				return notFoundError("Profile %s is not found. Use: bizfly config profile add %s", value, value)
+			}
+		case secretStoreKey:
+			if _, ok := SliceContains(secretStoreNames, value); !ok {
+				return usageError("Invalid secret store %s. Valid stores: %s", value, strings.Join(secretStoreNames, ", "))
+			}
+		}
+		if _, secret := SliceContains(secretKeys, key); secret {
+			if err := setProfileSecret(cf, getActiveProfile(), key, value); err != nil {
+				return err
+			}
+		} else {
+			cf.Set(path, value)
+		}
+		if err := cf.Save(); err != nil {
+			return err
+		}
+		if key != currentProfileKey && key != secretStoreKey {
+			if err := removeProfileTokenCache(getActiveProfile()); err != nil {
+				logging.Warnf("failed to remove cached tokens: %v", err)
+			}
+		}
+		logging.Infof("Set %s", key)
+		return nil
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset",
+	Short: "Remove a setting of the active profile",
+	Long: `Remove a setting of the active profile from the config file
+Use: bizfly config unset <key>
+Example: bizfly config unset auth_token`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return usageError("You need to specify the key. Use: bizfly config unset <key>")
+		}
+		key := args[0]
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		path, err := configKeyPath(cf, key)
+		if err != nil {
+			return err
+		}
+		var removed bool
+		if _, secret := SliceContains(secretKeys, key); secret {
+			if removed, err = unsetProfileSecret(cf, getActiveProfile(), key); err != nil {
+				return err
+			}
+		} else {
+			removed = cf.Unset(path)
+		}
+		if !removed {
+			return notFoundError("%s is not set", key)
+		}
+		if err := cf.Save(); err != nil {
+			return err
+		}
+		if key != currentProfileKey && key != secretStoreKey {
+			if err := removeProfileTokenCache(getActiveProfile()); err != nil {
+				logging.Warnf("failed to remove cached tokens: %v", err)
+			}
+		}
+		logging.Infof("Unset %s", key)
+		return nil
+	},
+}
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Print the config file",
+	Long: `Print the config file. Secrets are redacted unless --show-secrets is given.
+Use: bizfly config view`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		data := cf.data
+		if !showSecrets {
+			data = cf.Redacted()
+		}
+		b, err := yaml.Marshal(data)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(b))
+		return nil
+	},
+}
+
+var configMigrateSecretsCmd = &cobra.Command{
+	Use:   "migrate-secrets",
+	Short: "Move the secrets of the config file to a secret store",
+	Long: `Move the passwords, application credential secrets and tokens of all the profiles from the
+config file to the keyring or to an encrypted file, and use this store from now on.
+The keyring is used when it is available, the encrypted file otherwise. The encrypted file
+needs a passphrase in BIZFLY_CLOUD_SECRET_PASSPHRASE.
+Use: bizfly config migrate-secrets [--store keyring|file]
+Example: BIZFLY_CLOUD_SECRET_PASSPHRASE=... bizfly config migrate-secrets --store file`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cf, err := loadConfigFile()
+		if err != nil {
+			return err
+		}
+		name := migrateStore
+		if name == "" {
+			name = defaultMigrateStore()
+		}
+		if name != secretStoreKeyring && name != secretStoreFile {
+			return usageError("Invalid secret store %s. Secrets can be moved to %s or %s", name, secretStoreKeyring, secretStoreFile)
+		}
+		store, err := newSecretStore(name, cf)
+		if err != nil {
+			return err
+		}
+		profiles := cf.Profiles()
+		if !cf.HasProfile(defaultProfile) {
+			profiles = append([]string{defaultProfile}, profiles...)
+		}
+		moved := 0
+		for _, profile := range profiles {
+			for _, key := range secretKeys {
+				path := cf.ProfilePath(profile, key)
+				value, ok := cf.Get(path)
+				if !ok || value == nil || fmt.Sprint(value) == "" {
+					continue
+				}
+				if dryRunLocal("move %s of profile %s to %s", key, profile, secretStoreLocation(name)) {
+					moved++
+					continue
+				}
+				if err := store.Set(profile, key, fmt.Sprint(value)); err != nil {
+					return fmt.Errorf("failed to move %s of profile %s: %w", key, profile, err)
+				}
+				// the value is only removed from the config file once it can be read back
+				stored, err := store.Get(profile, key)
+				if err != nil {
+					return fmt.Errorf("failed to move %s of profile %s: %w", key, profile, err)
+				}
+				if stored != fmt.Sprint(value) {
+					return fmt.Errorf("failed to move %s of profile %s: %s does not return the stored value", key, profile, secretStoreLocation(name))
+				}
+				cf.Unset(path)
+				logging.Infof("Moved %s of profile %s to %s", key, profile, secretStoreLocation(name))
+				moved++
+			}
+		}
+		cf.Set(secretStoreKey, name)
+		if err := cf.Save(); err != nil {
+			return err
+		}
+		if moved == 0 {
+			logging.Infof("No secrets found in the config file")
+		}
+		logging.Infof("Using %s to store the secrets", secretStoreLocation(name))
+		return nil
+	},
+}
+
+// defaultMigrateStore returns the store secrets are moved to: the one in use
+// unless it is the config file, else the keyring when it is available
+func defaultMigrateStore() string {
+	if name, err := secretStoreName(); err == nil && name != secretStorePlaintext {
+		return name
+	}
+	if keyringAvailable() {
+		return secretStoreKeyring
+	}
+	return secretStoreFile
+}
+
+// configKeys returns the keys accepted by config get, set and unset
+func configKeys() []string {
+	return append([]string{currentProfileKey, secretStoreKey}, profileKeys...)
+}
+
+// configKeyPath validates a key and returns its path in the config file
+func configKeyPath(cf *configFile, key string) (string, error) {
+	if key == currentProfileKey || key == secretStoreKey {
+		return key, nil
+	}
+	if _, ok := SliceContains(profileKeys, key); !ok {
+		return "", usageError("Invalid key %s. Valid keys: %s", key, strings.Join(configKeys(), ", "))
+	}
+	return cf.ProfilePath(getActiveProfile(), key), nil
+}
+
+// regionNames returns the canonical region names
+func regionNames() []string {
+	var names []string
+	for _, name := range constants.RegionMapping {
+		if _, ok := SliceContains(names, name); !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configProfileCmd)
+	configProfileCmd.AddCommand(configProfileAddCmd)
+	configProfileCmd.AddCommand(configProfileUseCmd)
+	configProfileCmd.AddCommand(configProfileListCmd)
+	configProfileCmd.AddCommand(configProfileDeleteCmd)
+	configProfileCmd.AddCommand(configProfileCurrentCmd)
+
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configMigrateSecretsCmd)
+
+	configGetCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Print secrets in plain text")
+	configViewCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Print secrets in plain text")
+	configMigrateSecretsCmd.Flags().StringVar(&migrateStore, "store", "", "Secret store to move the secrets to: keyring or file")
+}
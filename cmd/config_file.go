@@ -0,0 +1,276 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	defaultProfile    = "default"
+	currentProfileKey = "current_profile"
+	profilesKey       = "profiles"
+	redactedValue     = "********"
+)
+
+// profileKeys are the settings a profile can hold
+var profileKeys = []string{"email", "password", "app_credential_id", "app_credential_secret", "region", "project_id", "auth_token",
+	"api_url", "auth_url", "ca_cert", "insecure_skip_verify", "timeout"}
+
+// secretKeys are redacted when the config is printed
+var secretKeys = []string{"password", "app_credential_secret", "auth_token"}
+
+// configFile is the content of ~/.bizfly.yaml. It is edited as a yaml.MapSlice
+// to keep the order of the keys and the settings viper does not know about.
+type configFile struct {
+	path string
+	data yaml.MapSlice
+}
+
+// configFilePath returns the config file in use or the default one
+func configFilePath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	if used := viper.ConfigFileUsed(); used != "" {
+		return used, nil
+	}
+	home, err := homedir.Dir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home dir: %w", err)
+	}
+	return filepath.Join(home, ".bizfly.yaml"), nil
+}
+
+// loadConfigFile reads the config file, a missing file is an empty config
+func loadConfigFile() (*configFile, error) {
+	path, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+	cf := &configFile{path: path}
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cf, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(b, &cf.data); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	return cf, nil
+}
+
+// Save writes the config file, readable by the current user only
+func (cf *configFile) Save() error {
+	if dryRunLocal("write %s", cf.path) {
+		return nil
+	}
+	b, err := yaml.Marshal(cf.data)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(cf.path), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(cf.path, b, 0600); err != nil {
+		return fmt.Errorf("failed to save config file: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	return os.Chmod(cf.path, 0600)
+}
+
+// Get returns the value at a dotted key path, e.g. "profiles.staging.region"
+func (cf *configFile) Get(key string) (interface{}, bool) {
+	var node interface{} = cf.data
+	for _, part := range strings.Split(key, ".") {
+		m, ok := node.(yaml.MapSlice)
+		if !ok {
+			return nil, false
+		}
+		found := false
+		for _, item := range m {
+			if fmt.Sprint(item.Key) == part {
+				node, found = item.Value, true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// Set sets the value at a dotted key path, creating the parent maps
+func (cf *configFile) Set(key string, value interface{}) {
+	cf.data = setMapSlice(cf.data, strings.Split(key, "."), value)
+}
+
+// Unset removes the value at a dotted key path and reports whether it existed
+func (cf *configFile) Unset(key string) bool {
+	var removed bool
+	cf.data, removed = unsetMapSlice(cf.data, strings.Split(key, "."))
+	return removed
+}
+
+func setMapSlice(m yaml.MapSlice, path []string, value interface{}) yaml.MapSlice {
+	for i, item := range m {
+		if fmt.Sprint(item.Key) != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			m[i].Value = value
+		} else {
+			child, _ := item.Value.(yaml.MapSlice)
+			m[i].Value = setMapSlice(child, path[1:], value)
+		}
+		return m
+	}
+	if len(path) == 1 {
+		return append(m, yaml.MapItem{Key: path[0], Value: value})
+	}
+	return append(m, yaml.MapItem{Key: path[0], Value: setMapSlice(nil, path[1:], value)})
+}
+
+func unsetMapSlice(m yaml.MapSlice, path []string) (yaml.MapSlice, bool) {
+	for i, item := range m {
+		if fmt.Sprint(item.Key) != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return append(m[:i], m[i+1:]...), true
+		}
+		child, ok := item.Value.(yaml.MapSlice)
+		if !ok {
+			return m, false
+		}
+		child, removed := unsetMapSlice(child, path[1:])
+		m[i].Value = child
+		return m, removed
+	}
+	return m, false
+}
+
+// Profiles returns the names of the profiles defined in the config file
+func (cf *configFile) Profiles() []string {
+	var names []string
+	if profiles, ok := cf.Get(profilesKey); ok {
+		if m, ok := profiles.(yaml.MapSlice); ok {
+			for _, item := range m {
+				names = append(names, fmt.Sprint(item.Key))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Redacted returns a copy of the config with the secret values hidden
+func (cf *configFile) Redacted() yaml.MapSlice {
+	return redactMapSlice(cf.data)
+}
+
+func redactMapSlice(m yaml.MapSlice) yaml.MapSlice {
+	result := make(yaml.MapSlice, 0, len(m))
+	for _, item := range m {
+		switch value := item.Value.(type) {
+		case yaml.MapSlice:
+			item.Value = redactMapSlice(value)
+		default:
+			if _, ok := SliceContains(secretKeys, fmt.Sprint(item.Key)); ok && value != nil {
+				item.Value = redactedValue
+			}
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// HasProfile reports whether the profile is defined in the config file
+func (cf *configFile) HasProfile(name string) bool {
+	_, ok := cf.Get(profilesKey + "." + name)
+	return ok
+}
+
+// profileKey returns the key of a setting in the config file. The default
+// profile falls back to the top level keys when it is not defined under
+// "profiles", so that config files without profiles keep working.
+func profileKey(profile, key string) string {
+	if profile == defaultProfile && !viper.IsSet(profilesKey+"."+defaultProfile) {
+		return key
+	}
+	return profilesKey + "." + profile + "." + key
+}
+
+// getActiveProfile returns the profile selected by --profile,
+// BIZFLY_CLOUD_PROFILE or current_profile, in this order.
+func getActiveProfile() string {
+	if profileName != "" {
+		return profileName
+	}
+	if p := os.Getenv("BIZFLY_CLOUD_PROFILE"); p != "" {
+		return p
+	}
+	if p := viper.GetString(currentProfileKey); p != "" {
+		return p
+	}
+	return defaultProfile
+}
+
+// configValue returns a setting of the active profile. Environment
+// variables take precedence over the config file.
+func configValue(key string) string {
+	if v := os.Getenv("BIZFLY_CLOUD_" + strings.ToUpper(key)); v != "" {
+		return v
+	}
+	return viper.GetString(profileKey(getActiveProfile(), key))
+}
+
+// ProfilePath returns the key path of a profile setting in the config file
+func (cf *configFile) ProfilePath(profile, key string) string {
+	if profile == defaultProfile && !cf.HasProfile(defaultProfile) {
+		return key
+	}
+	return profilesKey + "." + profile + "." + key
+}
+
+// saveProfileSecret stores a secret of the active profile in the secret store
+// and returns the name of the store
+func saveProfileSecret(key, value string) (string, error) {
+	cf, err := loadConfigFile()
+	if err != nil {
+		return "", err
+	}
+	name, err := secretStoreName()
+	if err != nil {
+		return "", err
+	}
+	if err := setProfileSecret(cf, getActiveProfile(), key, value); err != nil {
+		return "", err
+	}
+	return name, cf.Save()
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	assumeYes bool
+	// confirmInput and confirmOutput are used for the confirmation prompt
+	confirmInput  io.Reader = os.Stdin
+	confirmOutput io.Writer = os.Stderr
+	// stdinIsTerminal tells whether the confirmation can be asked
+	stdinIsTerminal = func() bool { return isTerminal(os.Stdin) }
+)
+
+// addConfirmFlag adds --yes to a command which destroys resources
+func addConfirmFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Do not ask for a confirmation")
+}
+
+// isTerminal reports whether f is an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// confirmDestroy prints what a command is going to destroy and asks for a
+// confirmation, unless --yes or --dry-run is set. Without a terminal to ask
+// on, it fails so that scripts have to pass --yes.
+func confirmDestroy(summary string, details []string) error {
+	if assumeYes || dryRun {
+		return nil
+	}
+	fmt.Fprintln(confirmOutput, summary)
+	for _, detail := range details {
+		fmt.Fprintln(confirmOutput, "  "+detail)
+	}
+	if !stdinIsTerminal() {
+		return usageError("The input is not a terminal to confirm. Use --yes to confirm")
+	}
+	fmt.Fprint(confirmOutput, "Do you want to continue? [y/N]: ")
+	answer, err := bufio.NewReader(confirmInput).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return nil
+	}
+	return &cmdError{code: ExitError, err: errors.New("Cancelled, nothing was deleted")}
+}
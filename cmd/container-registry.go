@@ -18,9 +18,9 @@ package cmd
 import (
 	"fmt"
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
-	"log"
 	"strconv"
 	"strings"
 )
@@ -41,24 +41,27 @@ var containerRegistryCmd = &cobra.Command{
 	Short: "Bizfly Cloud Container Registry Interaction",
 	Long:  "Bizfly Cloud Container Registry Action: List, Create, Delete, Get Tags, Update, Delete Image Tag, Get Image Info",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("container registry called")
+		logging.Debugf("container registry called")
 	},
 }
 
 var repositoryListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List repositories",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		repos, err := client.ContainerRegistry.List(ctx, &gobizfly.ListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, repo := range repos {
 			data = append(data, []string{repo.Name, repo.LastPush, strconv.Itoa(repo.Pulls), strconv.FormatBool(repo.Public), repo.CreatedAt})
 		}
-		formatter.Output(repositoryHeader, data)
+		return formatter.Output(repositoryHeader, data, repos)
 	},
 }
 
@@ -67,24 +70,28 @@ var repositoryCreateCmd = &cobra.Command{
 	Short: "Create Container Registry repository",
 	Long: `Create Container Registry repository
 Usage: ./bizfly container-registry create <repo_name> (--public|--private)`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
 		if (!isPrivate && !isPublic) || (isPrivate && isPublic) {
-			log.Fatal("You need to specify repository is public or not")
+			return usageError("You need to specify repository is public or not")
 		}
 		isPublic = isPublic || !isPrivate
 		payload := &gobizfly.CreateRepositoryPayload{
 			Name:   args[0],
 			Public: isPublic,
 		}
-		err := client.ContainerRegistry.Create(ctx, payload)
+		err = client.ContainerRegistry.Create(ctx, payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Creating repository")
+		logging.Infof("Creating repository")
+		return nil
 	},
 }
 
@@ -93,16 +100,31 @@ var repositoryDeleteCmd = &cobra.Command{
 	Short: "Delete Container Registry repository",
 	Long: `Delete Container Registry repository
 Usage: ./bizfly container-registry delete <repo_name>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
+		}
+		repoTags, err := client.ContainerRegistry.GetTags(ctx, args[0])
+		if err != nil {
+			return err
 		}
-		err := client.ContainerRegistry.Delete(ctx, args[0])
+		details := []string{"Repository " + args[0]}
+		for _, tag := range repoTags.Tags {
+			details = append(details, "  image "+args[0]+":"+tag.Name)
+		}
+		if err := confirmDestroy("The repository and all its images will be deleted:", details); err != nil {
+			return err
+		}
+		err = client.ContainerRegistry.Delete(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Deleting repository")
+		logging.Infof("Deleting repository")
+		return nil
 	},
 }
 
@@ -111,14 +133,17 @@ var getTagCmd = &cobra.Command{
 	Short: "Get repository Tags",
 	Long: `Get Repository Tags
 Usage: ./bizfly container-registry get-tags <repo_name>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
 		repoTags, err := client.ContainerRegistry.GetTags(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var tagsData [][]string
 		tags := repoTags.Tags
@@ -126,7 +151,7 @@ Usage: ./bizfly container-registry get-tags <repo_name>`,
 			tagsData = append(tagsData, []string{tag.Name, tag.Author, tag.LastUpdated, tag.CreatedAt, tag.LastScan,
 				tag.ScanStatus, strconv.Itoa(tag.Vulnerabilities), strconv.Itoa(tag.Fixes)})
 		}
-		formatter.Output(tagHeader, tagsData)
+		return formatter.Output(tagHeader, tagsData, repoTags)
 	},
 }
 
@@ -135,23 +160,27 @@ var editRepoCmd = &cobra.Command{
 	Short: "Edit Container Registry repository",
 	Long: `Edit Container Registry repository
 Usage: ./bizfly edit-repo <repo_name> (--public|--private)`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
 		if (!isPrivate && !isPublic) || (isPrivate && isPublic) {
-			log.Fatal("You need to specify repository is public or not")
+			return usageError("You need to specify repository is public or not")
 		}
 		isPublic = isPublic || !isPrivate
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		payload := &gobizfly.EditRepositoryPayload{
 			Public: isPublic,
 		}
-		err := client.ContainerRegistry.EditRepo(ctx, args[0], payload)
+		err = client.ContainerRegistry.EditRepo(ctx, args[0], payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Edit repository successfully")
+		logging.Infof("Edit repository successfully")
+		return nil
 	},
 }
 
@@ -160,16 +189,20 @@ var deleteTagCmd = &cobra.Command{
 	Short: "Delete Repository Tag",
 	Long: `Delete Repository Tag
 Usage: ./bizfly container-registry delete-tag <repo_name> <tag_name>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 2 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
-		err := client.ContainerRegistry.DeleteTag(ctx, args[0], args[1])
+		err = client.ContainerRegistry.DeleteTag(ctx, args[0], args[1])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Delete tag of repository successfully")
+		logging.Infof("Delete tag of repository successfully")
+		return nil
 	},
 }
 
@@ -178,14 +211,17 @@ var getImageCmd = &cobra.Command{
 	Short: "Get repository tag",
 	Long: `Get repository tag
 Usage: ./bizfly container-registry get-image <repo_name> <tag_name> [flags]`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 2 {
-			log.Fatal("Invalid arguments")
+			return usageError("Invalid arguments")
 		}
 		image, err := client.ContainerRegistry.GetTag(ctx, args[0], args[1], vulnerabilities)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		vulnerabilities := image.Vulnerabilities
 		var vulnerabilitiesData [][]string
@@ -194,7 +230,7 @@ Usage: ./bizfly container-registry get-image <repo_name> <tag_name> [flags]`,
 				[]string{vulnerability.Package, vulnerability.Name, vulnerability.Namespace,
 					vulnerability.Link, vulnerability.Severity, vulnerability.FixedBy})
 		}
-		formatter.Output(vulnerabilityHeader, vulnerabilitiesData)
+		return formatter.Output(vulnerabilityHeader, vulnerabilitiesData, image)
 	},
 }
 
@@ -209,32 +245,40 @@ Define:
    - repository: Repository name or namespace (which ends with /). Leave blank in order to grant token to all repositories
 Example: ./bizfly container-registry gen-token --expires-in 3404 --scope "actions:pull,push;repository:" --scope "actions:push;repository:test"
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		scopes, err := parseScope(scope)
+		if err != nil {
+			return err
+		}
 		payload := &gobizfly.GenerateTokenPayload{
 			ExpiresIn: expiresIn,
-			Scopes:    parseScope(scope),
+			Scopes:    scopes,
 		}
 		resp, err := client.ContainerRegistry.GenerateToken(ctx, payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		fmt.Println("Token:", resp.Token)
+		return nil
 	},
 }
 
-func parseScope(scopes []string) []gobizfly.Scope {
+func parseScope(scopes []string) ([]gobizfly.Scope, error) {
 	var scopeObjs []gobizfly.Scope
 	for _, scope := range scopes {
 		var scopeObj gobizfly.Scope
 		fragments := strings.Split(scope, ";")
 		if len(fragments) == 0 {
-			log.Fatal("Invalid argument: scope")
+			return nil, usageError("Invalid argument: scope")
 		}
 		for _, fragment := range fragments {
 			keyValue := strings.Split(fragment, ":")
 			if len(keyValue) != 2 {
-				log.Fatal("Invalid argument: scope")
+				return nil, usageError("Invalid argument: scope")
 			}
 			key := keyValue[0]
 			value := keyValue[1]
@@ -248,7 +292,7 @@ func parseScope(scopes []string) []gobizfly.Scope {
 		}
 		scopeObjs = append(scopeObjs, scopeObj)
 	}
-	return scopeObjs
+	return scopeObjs, nil
 }
 
 func init() {
@@ -261,6 +305,7 @@ func init() {
 	rcpf.BoolVar(&isPrivate, "private", false, "Is private or not")
 	containerRegistryCmd.AddCommand(repositoryCreateCmd)
 
+	addConfirmFlag(repositoryDeleteCmd)
 	containerRegistryCmd.AddCommand(repositoryDeleteCmd)
 
 	containerRegistryCmd.AddCommand(getTagCmd)
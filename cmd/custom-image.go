@@ -18,13 +18,13 @@ package cmd
 import (
 	"fmt"
 	"io"
-	"log"
 	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -43,7 +43,7 @@ var customImageCmd = &cobra.Command{
 	Short: "BizFly Custom Image Interaction",
 	Long:  "BizFly Custom Image Action: List, Create, Delete",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("custom image called")
+		logging.Debugf("custom image called")
 	},
 }
 
@@ -51,18 +51,21 @@ var customImageList = &cobra.Command{
 	Use:   "list",
 	Short: "List custom images",
 	Long:  "List your custom images",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		images, err := client.CloudServer.CustomImages().List(ctx)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, image := range images {
 			data = append(data, []string{image.ID, image.Name, image.Description,
 				image.DiskFormat, strconv.Itoa(image.Size), image.Status, image.Visibility})
 		}
-		formatter.Output(customImageHeader, data)
+		return formatter.Output(customImageHeader, data, images)
 	},
 }
 
@@ -71,13 +74,16 @@ var customImageCreate = &cobra.Command{
 	Short: "Create a new custom image",
 	Long: `Create a new custom image with name, image URL
 Example: bizfly custom-image create --name xyz --disk-format raw --description abcxyz --image-url http://xyz.abc`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if imageURL == "" && filePath == "" {
-			log.Fatal("Invalid arguments. You need to specify image-url or file-path")
+			return usageError("Invalid arguments. You need to specify image-url or file-path")
 		} else if imageURL != "" && filePath != "" {
-			log.Fatal("Invalid arguments. You need to specify image-url or file-path")
+			return usageError("Invalid arguments. You need to specify image-url or file-path")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		if imageURL != "" {
 			resp, err := client.CloudServer.CustomImages().Create(ctx, &gobizfly.CreateCustomImagePayload{
 				Name:        customImageName,
@@ -86,13 +92,13 @@ Example: bizfly custom-image create --name xyz --disk-format raw --description a
 				ImageURL:    imageURL,
 			})
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			image := resp.Image
 			var data [][]string
 			data = append(data, []string{image.ID, image.Name, image.Description,
 				image.DiskFormat, strconv.Itoa(image.Size), image.Status, image.Visibility})
-			formatter.Output(customImageHeader, data)
+			return formatter.Output(customImageHeader, data, image)
 		} else {
 			resp, err := client.CloudServer.CustomImages().Create(ctx, &gobizfly.CreateCustomImagePayload{
 				Name:        customImageName,
@@ -100,40 +106,40 @@ Example: bizfly custom-image create --name xyz --disk-format raw --description a
 				Description: description,
 			})
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			file, err := os.Open(filePath)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			defer func() {
 				if err := file.Close(); err != nil {
-					log.Printf("failed to close upload file: %v", err)
+					logging.Warnf("failed to close upload file: %v", err)
 				}
 			}()
-			fmt.Println(resp.UploadURI)
+			logging.Verbosef("Uploading image to %s", resp.UploadURI)
 			r, err := http.NewRequest("PUT", resp.UploadURI, file)
 
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			r.Header.Set("X-Auth-Token", resp.Token)
 			r.Header.Set("Content-Type", "application/octet-stream")
 			client := &http.Client{}
 			response, err := client.Do(r)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			defer func() {
 				if err := response.Body.Close(); err != nil {
-					log.Printf("failed to close upload response body: %v", err)
+					logging.Warnf("failed to close upload response body: %v", err)
 				}
 			}()
 			image := resp.Image
 			var data [][]string
 			data = append(data, []string{image.ID, image.Name, image.Description,
 				image.DiskFormat, strconv.Itoa(image.Size), image.Status, image.Visibility})
-			formatter.Output(customImageHeader, data)
+			return formatter.Output(customImageHeader, data, image)
 		}
 	},
 }
@@ -142,17 +148,21 @@ var customImageDelete = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a custom image",
 	Long:  "Delete a custom image using its ID",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.CloudServer.CustomImages().Delete(ctx, args[0])
+		err = client.CloudServer.CustomImages().Delete(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		} else {
-			fmt.Println("Delete the custom image successfully")
+			logging.Infof("Delete the custom image successfully")
 		}
+		return nil
 	},
 }
 
@@ -160,11 +170,14 @@ var customImageDownload = &cobra.Command{
 	Use:   "download",
 	Short: "Download a custom image",
 	Long:  "Download a custom image using its ID",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		resp, err := client.CloudServer.CustomImages().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		image := resp.Image
@@ -172,49 +185,47 @@ var customImageDownload = &cobra.Command{
 
 		if image.ID == args[0] {
 			if image.Status != "active" {
-				log.Fatalf("Image %s is not ready to download. Status %s", image.ID, image.Status)
-				return
+				return usageError("Image %s is not ready to download. Status %s", image.ID, image.Status)
 			}
 			downloadURL := image.File
 			fileName := fmt.Sprintf("%s.%s", image.Name, image.DiskFormat)
 			file, err := os.Create(filepath.Join(downloadPath, fileName))
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			defer func() {
 				if err := file.Close(); err != nil {
-					log.Printf("failed to close download file: %v", err)
+					logging.Warnf("failed to close download file: %v", err)
 				}
 			}()
 			client := http.Client{}
 			req, err := http.NewRequest(http.MethodGet, downloadURL, nil)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			req.Header.Set("X-Auth-Token", token)
 			resp, err := client.Do(req)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			defer func() {
 				if err := resp.Body.Close(); err != nil {
-					log.Printf("failed to close download response body: %v", err)
+					logging.Warnf("failed to close download response body: %v", err)
 				}
 			}()
 			if resp.StatusCode != 200 {
-				log.Fatalf("Download image failed. Status code %d", resp.StatusCode)
-				return
+				return fmt.Errorf("Download image failed. Status code %d", resp.StatusCode)
 			}
 			size, err := io.Copy(file, resp.Body)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
-			fmt.Printf("Downloaded a file %s with size %d Bytes\n", fileName, size)
+			logging.Infof("Downloaded a file %s with size %d Bytes", fileName, size)
 
 			data = append(data, []string{image.ID, image.Name, image.Description,
 				image.DiskFormat, strconv.Itoa(image.Size), image.Status, image.Visibility})
 		}
-		formatter.Output(customImageHeader, data)
+		return formatter.Output(customImageHeader, data, image)
 	},
 }
 
@@ -0,0 +1,251 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// ANSI colors of the diff
+const (
+	colorReset  = "\033[0m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorCyan   = "\033[36m"
+)
+
+var diffColor string
+
+// diffResult is the change of a resource for the structured output
+type diffResult struct {
+	Kind    string        `json:"kind" yaml:"kind"`
+	Name    string        `json:"name" yaml:"name"`
+	ID      string        `json:"id,omitempty" yaml:"id,omitempty"`
+	Action  string        `json:"action" yaml:"action"`
+	Changes []fieldChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show what apply would change",
+	Long: `Compare the resources of a manifest with the existing ones and print the changes apply would
+make as a unified diff. Nothing is changed.
+apply never deletes resources: the existing resources which are not in the manifest, even with its
+labels, are left as they are and are not shown.
+Exit with 0 when there are no changes and 1 when there are. Every failure exits with a code
+of 2 or more, so that it is never taken for changes: 2 for an invalid manifest, flag or output format, 6 for
+an API error which has no more precise code.
+Use: bizfly diff -f <manifest.yaml>`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		colored, err := useColor(diffColor)
+		if err != nil {
+			return err
+		}
+		resources, err := readManifests(manifestFiles)
+		if err != nil {
+			return diffFailure(err, ExitUsage)
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return diffFailure(err, ExitAPI)
+		}
+		var plans []*manifestPlan
+		for _, res := range resources {
+			plan, err := planManifest(ctx, client, res)
+			if err != nil {
+				return diffFailure(err, ExitAPI)
+			}
+			plans = append(plans, plan)
+		}
+		changed := false
+		for _, plan := range plans {
+			if plan.action != actionUnchanged {
+				changed = true
+			}
+		}
+		if formatter.IsStructured() {
+			results := make([]diffResult, 0, len(plans))
+			for _, plan := range plans {
+				result := diffResult{Kind: plan.resource.Kind, Name: plan.resource.Metadata.Name, Action: plan.action, Changes: plan.changes}
+				if plan.live != nil {
+					result.ID = plan.live.id
+				}
+				results = append(results, result)
+			}
+			if err := formatter.Output(nil, nil, results); err != nil {
+				return diffFailure(err, ExitUsage)
+			}
+		} else {
+			out := cmd.OutOrStdout()
+			for _, plan := range plans {
+				if plan.action != actionUnchanged {
+					writePlanDiff(out, plan, colored)
+				}
+			}
+			if !changed {
+				fmt.Fprintln(out, "No changes")
+			}
+		}
+		if changed {
+			return exitStatus(ExitError)
+		}
+		return nil
+	},
+}
+
+// diffFailure returns err with code when it would exit with 1, which diff
+// keeps for the changes
+func diffFailure(err error, code int) error {
+	if exitCode(err) > ExitError {
+		return err
+	}
+	return &cmdError{code: code, err: err}
+}
+
+// useColor tells whether the output is colored for --color
+func useColor(mode string) (bool, error) {
+	switch mode {
+	case "always":
+		return true, nil
+	case "never":
+		return false, nil
+	case "auto":
+		if os.Getenv("NO_COLOR") != "" {
+			return false, nil
+		}
+		return isTerminal(os.Stdout), nil
+	}
+	return false, usageError("Invalid --color %q, use auto, always or never", mode)
+}
+
+// writePlanDiff prints the change of a resource as a unified diff of its spec
+func writePlanDiff(w io.Writer, plan *manifestPlan, colored bool) {
+	res := plan.resource
+	paint := func(color, line string) {
+		if colored {
+			fmt.Fprintln(w, color+line+colorReset)
+		} else {
+			fmt.Fprintln(w, line)
+		}
+	}
+	var from, to []string
+	if plan.action == actionCreate {
+		paint(colorYellow, fmt.Sprintf("# %s will be created (%s)", res, res.source))
+		paint(colorYellow, "--- /dev/null")
+		to = yamlLines(res.spec)
+	} else {
+		paint(colorYellow, fmt.Sprintf("# %s (%s) will be updated (%s)", res, plan.live.id, res.source))
+		for _, change := range plan.changes {
+			if !change.Updatable {
+				paint(colorYellow, fmt.Sprintf("# %s can only be set at creation, apply leaves it unchanged", change.Field))
+			}
+		}
+		paint(colorYellow, fmt.Sprintf("--- %s (live)", res))
+		liveFields := make(yaml.MapSlice, 0, len(plan.changes))
+		desiredFields := make(yaml.MapSlice, 0, len(plan.changes))
+		for _, change := range plan.changes {
+			if change.From != nil {
+				liveFields = append(liveFields, yaml.MapItem{Key: change.Field, Value: change.From})
+			}
+			desiredFields = append(desiredFields, yaml.MapItem{Key: change.Field, Value: change.To})
+		}
+		from = yamlLines(liveFields)
+		to = yamlLines(desiredFields)
+	}
+	paint(colorYellow, fmt.Sprintf("+++ %s (manifest)", res))
+	paint(colorCyan, fmt.Sprintf("@@ -%s +%s @@", hunkRange(len(from)), hunkRange(len(to))))
+	for _, line := range diffLines(from, to) {
+		switch line[0] {
+		case '-':
+			paint(colorRed, line)
+		case '+':
+			paint(colorGreen, line)
+		default:
+			fmt.Fprintln(w, line)
+		}
+	}
+}
+
+func hunkRange(lines int) string {
+	if lines == 0 {
+		return "0,0"
+	}
+	return fmt.Sprintf("1,%d", lines)
+}
+
+func yamlLines(v interface{}) []string {
+	b, err := yaml.Marshal(v)
+	if err != nil || len(b) == 0 || string(b) == "{}\n" || string(b) == "[]\n" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+// diffLines returns the lines of from and to prefixed with "-", "+" or " ",
+// from their longest common subsequence
+func diffLines(from, to []string) []string {
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var lines []string
+	i, j := 0, 0
+	for i < len(from) || j < len(to) {
+		switch {
+		case i < len(from) && j < len(to) && from[i] == to[j]:
+			lines = append(lines, " "+from[i])
+			i++
+			j++
+		case i < len(from) && (j == len(to) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "-"+from[i])
+			i++
+		default:
+			lines = append(lines, "+"+to[j])
+			j++
+		}
+	}
+	return lines
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	dcf := diffCmd.Flags()
+	dcf.StringArrayVarP(&manifestFiles, "filename", "f", []string{}, "Manifest file, - for the standard input. Can be repeated")
+	// not marked required: cobra exits with 1 for a missing required flag, readManifests
+	// reports it as a usage error
+	dcf.StringVar(&diffColor, "color", "auto", "Color the diff: auto, always or never")
+}
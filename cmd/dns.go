@@ -1,13 +1,12 @@
 package cmd
 
 import (
-	"encoding/json"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -42,18 +41,21 @@ var dnsComnmand = &cobra.Command{
 	Short: "Bizfly Cloud DNS Interaction",
 	Long:  "Bizfly Cloud DNS Action: List zones, Create zone, Get zone, Delete zone, Create record, Get record, Delete record",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("dns called")
+		logging.Debugf("dns called")
 	},
 }
 
 var listZonesCommand = &cobra.Command{
 	Use:   "list-zones",
 	Short: "List all zones",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		resp, err := client.DNS.ListZones(ctx, &gobizfly.ListOptions{})
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		zones := resp.Zones
 		var data [][]string
@@ -63,7 +65,7 @@ var listZonesCommand = &cobra.Command{
 				nameserverString, strconv.Itoa(zone.TTL), strconv.FormatBool(zone.Active),
 				zone.CreatedAt, zone.UpdatedAt})
 		}
-		formatter.Output(zonesHeader, data)
+		return formatter.Output(zonesHeader, data, zones)
 	},
 }
 
@@ -72,14 +74,17 @@ var getZoneCommand = &cobra.Command{
 	Short: "Get a zone",
 	Long: `Get a zone
 Usage: ./bizfly dns get-zone <zone-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
 		resp, err := client.DNS.GetZone(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		zone := resp.Zone
 		recordSets := resp.RecordsSet
@@ -95,8 +100,13 @@ Usage: ./bizfly dns get-zone <zone-id>`,
 			recordSetData = append(recordSetData, []string{recordSet.ID, recordSet.Name,
 				recordSet.Type, strconv.Itoa(recordSet.TTL)})
 		}
-		formatter.Output(zonesHeader, zoneData)
-		formatter.Output(recordSetHeader, recordSetData)
+		if err := formatter.Output(zonesHeader, zoneData, resp); err != nil {
+			return err
+		}
+		if !formatter.IsStructured() {
+			return formatter.Output(recordSetHeader, recordSetData, recordSets)
+		}
+		return nil
 	},
 }
 
@@ -105,10 +115,13 @@ var createZoneCommand = &cobra.Command{
 	Short: "Create DNS Zone",
 	Long: `Create DNS Zone
 Usage: ./bizfly dns create-zone <zone-name> [flags]`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
 		payload := &gobizfly.CreateZonePayload{
 			Name:        args[0],
@@ -117,7 +130,7 @@ Usage: ./bizfly dns create-zone <zone-name> [flags]`,
 		}
 		resp, err := client.DNS.CreateZone(ctx, payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		zone := resp.Zone
 		recordSets := resp.RecordsSet
@@ -133,8 +146,13 @@ Usage: ./bizfly dns create-zone <zone-name> [flags]`,
 			recordSetData = append(recordSetData, []string{recordSet.ID, recordSet.Name,
 				recordSet.Type, strconv.Itoa(recordSet.TTL)})
 		}
-		formatter.Output(zonesHeader, zoneData)
-		formatter.Output(recordSetHeader, recordSetData)
+		if err := formatter.Output(zonesHeader, zoneData, resp); err != nil {
+			return err
+		}
+		if !formatter.IsStructured() {
+			return formatter.Output(recordSetHeader, recordSetData, recordSets)
+		}
+		return nil
 	},
 }
 
@@ -143,16 +161,31 @@ var deleteZoneCommand = &cobra.Command{
 	Short: "Delete zone",
 	Long: `Delete zone
 Usage: ./bizfly dns delete zone <zone-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
-		err := client.DNS.DeleteZone(ctx, args[0])
+		resp, err := client.DNS.GetZone(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		details := []string{fmt.Sprintf("Zone %s (%s)", resp.Zone.Name, resp.Zone.ID)}
+		for _, recordSet := range resp.RecordsSet {
+			details = append(details, fmt.Sprintf("  record %s %s", recordSet.Name, recordSet.Type))
+		}
+		if err := confirmDestroy("The zone and all its records will be deleted:", details); err != nil {
+			return err
 		}
-		fmt.Println("Deleted Zone ", zoneID)
+		err = client.DNS.DeleteZone(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		logging.Infof("Deleted Zone %s", args[0])
+		return nil
 	},
 }
 
@@ -161,20 +194,25 @@ var getRecordCommand = &cobra.Command{
 	Short: "Get record via ID",
 	Long: `Get DNS record in a zone
 Usage: ./bizfly dns get-record <record-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
 		recordSet, err := client.DNS.GetRecord(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var recordSetData [][]string
 		recordSetData = append(recordSetData, []string{recordSet.ID, recordSet.Name,
 			recordSet.Type, strconv.Itoa(recordSet.TTL)})
-		formatter.Output(recordSetHeader, recordSetData)
-		outputRecordData(recordSet)
+		if err := formatter.Output(recordSetHeader, recordSetData, recordSet); err != nil {
+			return err
+		}
+		return outputRecordData(recordSet)
 	},
 }
 
@@ -194,13 +232,16 @@ Type arguments:
     + domain-data: specify the domains and its priority. Format: --domain-data domain:priority
     Example: ./bizfly dns create-record --zone-id 123-zone --name test_mx_1 --ttl 600 --type MX --domain-data test.com:10 --domain-data test1.com:49
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if checkValidType(recordType, NormalTypes) { // Normal type case
 			stringRecordData := fmt.Sprintf("%v", recordData)
 			data := parseNormalRecord(stringRecordData)
 			if len(data) == 0 {
-				log.Fatal("Invalid argument")
+				return usageError("Invalid argument")
 			}
 			payloadData := gobizfly.CreateNormalRecordPayload{
 				BaseCreateRecordPayload: gobizfly.BaseCreateRecordPayload{
@@ -213,12 +254,9 @@ Type arguments:
 			payload := recordPayload{
 				Record: payloadData,
 			}
-			fmt.Printf("%+v\n", payload)
-			json_data, _ := json.Marshal(payload)
-			fmt.Println(string(json_data))
 			recordSet, err := client.DNS.CreateRecord(ctx, zoneID, payload)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			var recordSetData [][]string
 			stringData := ""
@@ -227,10 +265,15 @@ Type arguments:
 			}
 			recordSetData = append(recordSetData, []string{recordSet.ID, recordSet.Name,
 				recordSet.Type, strconv.Itoa(recordSet.TTL), stringData})
-			formatter.Output(recordSetHeader, recordSetData)
-			outputRecordData(recordSet)
+			if err := formatter.Output(recordSetHeader, recordSetData, recordSet); err != nil {
+				return err
+			}
+			return outputRecordData(recordSet)
 		} else if recordType == "MX" {
-			mxData := parseMXRecord(domainData)
+			mxData, err := parseMXRecord(domainData)
+			if err != nil {
+				return err
+			}
 			payloadData := gobizfly.CreateMXRecordPayload{
 				BaseCreateRecordPayload: gobizfly.BaseCreateRecordPayload{
 					Name: recordName,
@@ -244,14 +287,17 @@ Type arguments:
 			}
 			recordSet, err := client.DNS.CreateRecord(ctx, zoneID, payload)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			var recordSetData [][]string
 			recordSetData = append(recordSetData, []string{recordSet.ID, recordSet.Name,
 				recordSet.Type, strconv.Itoa(recordSet.TTL)})
-			formatter.Output(recordSetHeader, recordSetData)
-			outputRecordData(recordSet)
+			if err := formatter.Output(recordSetHeader, recordSetData, recordSet); err != nil {
+				return err
+			}
+			return outputRecordData(recordSet)
 		}
+		return nil
 	},
 }
 
@@ -260,26 +306,34 @@ var deleteRecordCommand = &cobra.Command{
 	Short: "Delete DNS record",
 	Long: `Delete DNS record
 Usage: ./bizfly dns delete-record <record-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
-		err := client.DNS.DeleteRecord(ctx, args[0])
+		err = client.DNS.DeleteRecord(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Deleted record successfully")
+		logging.Infof("Deleted record successfully")
+		return nil
 	},
 }
 
-func outputRecordData(record *gobizfly.Record) {
+func outputRecordData(record *gobizfly.Record) error {
+	// the record data is already part of the serialized record
+	if formatter.IsStructured() {
+		return nil
+	}
 	if checkValidType(record.Type, NormalTypes) {
 		var IPs [][]string
 		for _, ip := range record.Data {
 			IPs = append(IPs, []string{ip.(string)})
 		}
-		formatter.Output(NormalDataHeader, IPs)
+		return formatter.Output(NormalDataHeader, IPs, record.Data)
 	} else if record.Type == "MX" {
 		var mxDatas [][]string
 		for _, domainData := range record.Data {
@@ -287,8 +341,9 @@ func outputRecordData(record *gobizfly.Record) {
 			priority := strconv.Itoa(int(domainMap["priority"].(float64)))
 			mxDatas = append(mxDatas, []string{domainMap["value"].(string), priority})
 		}
-		formatter.Output(MXDataHeader, mxDatas)
+		return formatter.Output(MXDataHeader, mxDatas, record.Data)
 	}
+	return nil
 }
 
 func checkValidType(recordType string, validTypes []string) bool {
@@ -305,18 +360,21 @@ func parseNormalRecord(data string) []string {
 	return strings.Split(data, ";")
 }
 
-func parseMXRecord(data []string) []gobizfly.MXData {
+func parseMXRecord(data []string) ([]gobizfly.MXData, error) {
 	var mxData []gobizfly.MXData
 	for _, recordString := range data {
 		fragments := strings.Split(recordString, ":")
 		domain := fragments[0]
+		if len(fragments) != 2 {
+			return nil, usageError("Invalid MX record %s. Use: <domain>:<priority>", recordString)
+		}
 		priority, err := strconv.Atoi(fragments[1])
 		if err != nil {
-			log.Fatal(err)
+			return nil, usageError("Invalid priority of MX record %s: %v", recordString, err)
 		}
 		mxData = append(mxData, gobizfly.MXData{Value: domain, Priority: priority})
 	}
-	return mxData
+	return mxData, nil
 }
 
 func init() {
@@ -330,6 +388,7 @@ func init() {
 	czpf.StringVar(&zoneDescription, "description", "", "Zone description")
 	dnsComnmand.AddCommand(createZoneCommand)
 
+	addConfirmFlag(deleteZoneCommand)
 	dnsComnmand.AddCommand(deleteZoneCommand)
 
 	crpf := createRecordCommand.PersistentFlags()
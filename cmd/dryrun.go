@@ -0,0 +1,88 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
+)
+
+var (
+	dryRun bool
+	// dryRunOut is where --dry-run prints the requests
+	dryRunOut io.Writer = os.Stdout
+)
+
+// dryRunTransport prints the requests which change resources instead of
+// sending them, and answers them with an empty JSON object so that the
+// commands go on and print all their requests. Reading requests and the
+// token requests, which carry no X-Auth-Token, are sent, so the names are
+// resolved and the input validated.
+type dryRunTransport struct {
+	base http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead || req.Header.Get("X-Auth-Token") == "" {
+		return t.base.RoundTrip(req)
+	}
+	// the output of the commands would describe the empty responses
+	formatter.SetOutput(io.Discard)
+	fmt.Fprintf(dryRunOut, "%s %s\n", req.Method, req.URL)
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		var indented bytes.Buffer
+		if json.Indent(&indented, b, "", "  ") == nil {
+			b = indented.Bytes()
+		}
+		if len(b) > 0 {
+			fmt.Fprintln(dryRunOut, logging.Redact(string(b)))
+		}
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+// dryRunLocal reports whether --dry-run is set, and then prints the local
+// change which is not made, e.g. the write of the config file
+func dryRunLocal(format string, args ...interface{}) bool {
+	if !dryRun {
+		return false
+	}
+	fmt.Fprintf(dryRunOut, "Would "+format+"\n", args...)
+	return true
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bizflycloud/bizflyctl/formatter"
+)
+
+type countingTransport struct {
+	requests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests++
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`[]`)), Request: req}, nil
+}
+
+func TestDryRunTransport(t *testing.T) {
+	var out bytes.Buffer
+	dryRunOut = &out
+	defer func() {
+		dryRunOut = os.Stdout
+		formatter.SetOutput(os.Stdout)
+	}()
+	base := &countingTransport{}
+	transport := &dryRunTransport{base: base}
+	for _, body := range []string{`{"name":"web-1"}`, `{"name":"web-2"}`} {
+		req, _ := http.NewRequest(http.MethodPost, "https://api.example.com/servers", strings.NewReader(body))
+		req.Header.Set("X-Auth-Token", "token")
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusOK || string(b) != "{}" {
+			t.Errorf("got %d %q, want an empty object", resp.StatusCode, b)
+		}
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com/servers", nil)
+	req.Header.Set("X-Auth-Token", "token")
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	if base.requests != 1 {
+		t.Errorf("%d requests sent, want only the GET", base.requests)
+	}
+	want := "POST https://api.example.com/servers\n{\n  \"name\": \"web-1\"\n}\nPOST https://api.example.com/servers\n{\n  \"name\": \"web-2\"\n}\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/bizflycloud/bizflyctl/logging"
+)
+
+const (
+	// defaultAPIURL is the Bizfly Cloud API, also used to validate the login tickets
+	defaultAPIURL = "https://manage.bizflycloud.vn"
+	// defaultAuthURL is the login page of Bizfly Cloud
+	defaultAuthURL = "https://id.bizflycloud.vn"
+)
+
+var (
+	apiURL             string
+	authURL            string
+	caCertFile         string
+	insecureSkipVerify bool
+)
+
+// endpointSetting returns the value of a flag, or of its config key when the
+// flag is not set
+func endpointSetting(flagValue, key string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	return configValue(key)
+}
+
+// apiEndpoint returns the API URL of --api-url or api_url, or the default one
+func apiEndpoint() (string, error) {
+	return endpointURL("api-url", endpointSetting(apiURL, "api_url"), defaultAPIURL)
+}
+
+// authEndpoint returns the URL of the login page of --auth-url or auth_url,
+// or the default one
+func authEndpoint() (string, error) {
+	return endpointURL("auth-url", endpointSetting(authURL, "auth_url"), defaultAuthURL)
+}
+
+func endpointURL(flag, value, defaultURL string) (string, error) {
+	if value == "" {
+		return defaultURL, nil
+	}
+	u, err := url.Parse(value)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", usageError("Invalid --%s %q, it must be an http or https URL", flag, value)
+	}
+	return strings.TrimRight(value, "/"), nil
+}
+
+// newHTTPTransport returns the transport of the API requests with the CA of
+// --ca-cert and --insecure-skip-verify
+func newHTTPTransport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	caCert := endpointSetting(caCertFile, "ca_cert")
+	insecure := insecureSkipVerify
+	if !insecure {
+		if v := configValue("insecure_skip_verify"); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, usageError("Invalid insecure_skip_verify %q, use true or false", v)
+			}
+			insecure = b
+		}
+	}
+	if caCert == "" && !insecure {
+		return transport, nil
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caCert != "" {
+		pem, err := os.ReadFile(caCert)
+		if err != nil {
+			return nil, usageError("Failed to read the CA certificate: %v", err)
+		}
+		// the CA is added to the system ones, so the public endpoints keep working
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, usageError("No PEM certificate found in %s", caCert)
+		}
+		tlsConfig.RootCAs = pool
+		logging.Verbosef("Using the CA certificate %s", caCert)
+	}
+	if insecure {
+		logging.Warnf("TLS certificates are not verified, do not use --insecure-skip-verify in production")
+		tlsConfig.InsecureSkipVerify = true // nolint:gosec
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// newHTTPClient returns a client for the requests which are not sent by gobizfly
+func newHTTPClient() (*http.Client, error) {
+	timeout, err := apiTimeout()
+	if err != nil {
+		return nil, err
+	}
+	transport, err := newHTTPTransport()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: &retryTransport{base: logging.NewTransport(transport), timeout: timeout,
+		maxRetries: defaultMaxRetries}}, nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bizflycloud/gobizfly"
+)
+
+// Exit codes of bizfly, documented in docs/exit-codes.md
+const (
+	// ExitError is returned for errors which do not fit another code
+	ExitError = 1
+	// ExitUsage is returned for invalid arguments, flags or input rejected by the API
+	ExitUsage = 2
+	// ExitNotFound is returned when a resource does not exist
+	ExitNotFound = 3
+	// ExitAuth is returned when the credentials or the token are rejected
+	ExitAuth = 4
+	// ExitQuota is returned when the quota of the project is exceeded
+	ExitQuota = 5
+	// ExitAPI is returned when the API is unreachable or fails
+	ExitAPI = 6
+	// ExitTimeout is returned when --wait times out
+	ExitTimeout = 7
+)
+
+// cmdError is an error with the exit code of the command
+type cmdError struct {
+	code int
+	err  error
+}
+
+func (e *cmdError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cmdError) Unwrap() error {
+	return e.err
+}
+
+// usageError reports invalid arguments or flags
+func usageError(format string, args ...interface{}) error {
+	return &cmdError{code: ExitUsage, err: fmt.Errorf(format, args...)}
+}
+
+// notFoundError reports a resource which does not exist
+func notFoundError(format string, args ...interface{}) error {
+	return &cmdError{code: ExitNotFound, err: fmt.Errorf(format, args...)}
+}
+
+// authError reports credentials or a token which are rejected
+func authError(format string, args ...interface{}) error {
+	return &cmdError{code: ExitAuth, err: fmt.Errorf(format, args...)}
+}
+
+// errSilent is the error of a command which already reported its result
+var errSilent = errors.New("")
+
+// exitStatus returns an error which only sets the exit code of the command
+func exitStatus(code int) error {
+	return &cmdError{code: code, err: errSilent}
+}
+
+// isNotFound reports whether err is a missing resource
+func isNotFound(err error) bool {
+	var ce *cmdError
+	if errors.As(err, &ce) {
+		return ce.code == ExitNotFound
+	}
+	return errors.Is(err, gobizfly.ErrNotFound)
+}
+
+// exitCode returns the exit code of an error returned by a command
+func exitCode(err error) int {
+	var ce *cmdError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	if errors.Is(err, gobizfly.ErrNotFound) {
+		return ExitNotFound
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitAPI
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ExitAPI
+	}
+	// gobizfly does not expose the HTTP status, use the last failed response
+	status, body := apiStatus.last()
+	switch {
+	case status == 0:
+		return ExitError
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ExitAuth
+	case status == http.StatusNotFound:
+		return ExitNotFound
+	case status == http.StatusRequestEntityTooLarge || strings.Contains(strings.ToLower(body), "quota"):
+		return ExitQuota
+	case status >= http.StatusInternalServerError:
+		return ExitAPI
+	case status >= http.StatusBadRequest:
+		return ExitUsage
+	}
+	return ExitError
+}
+
+// maxErrorBody is the part of an error response kept to classify the error
+const maxErrorBody = 4096
+
+var apiStatus = &statusTransport{}
+
+// statusTransport records the last failed API response. A successful
+// response clears it, so that a failure recovered from, e.g. by a new token
+// or a retry, does not classify a later error.
+type statusTransport struct {
+	base   http.RoundTripper
+	mu     sync.Mutex
+	status int
+	body   string
+}
+
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	if resp.StatusCode < http.StatusBadRequest {
+		t.mu.Lock()
+		t.status, t.body = 0, ""
+		t.mu.Unlock()
+		return resp, nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(b), resp.Body), resp.Body}
+	t.mu.Lock()
+	t.status, t.body = resp.StatusCode, string(b)
+	t.mu.Unlock()
+	return resp, nil
+}
+
+func (t *statusTransport) last() (int, string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status, t.body
+}
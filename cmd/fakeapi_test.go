@@ -0,0 +1,139 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	fakeToken       = "fake-token"
+	fakeTokenExpiry = "2099-01-01T00:00:00Z"
+	fakeTokenPath   = "/api/token"
+	fakeCatalogPath = "/api/auth/service"
+)
+
+// fakeServices are the services of the fake catalog, served under /<name>/
+var fakeServices = []string{"cloud_server", "load_balancer", "kubernetes_engine", "dns",
+	"container_registry", "auto_scaling", "cloud_watcher", "iam", "kafka"}
+
+// fakeAPI is a local fake of the Bizfly Cloud API for the command tests.
+// GET requests are answered with the JSON fixture at the same path under
+// testdata/fakeapi, e.g. cloud_server/servers.json. Other methods are
+// answered with the fixture named after the method, e.g.
+// cloud_server/servers/<id>.DELETE.json. A missing fixture is a 404.
+type fakeAPI struct {
+	server *httptest.Server
+	// failures answers routes such as "GET /cloud_server/servers" with a status
+	failures map[string]int
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func newFakeAPI(t *testing.T, failures map[string]int) *fakeAPI {
+	t.Helper()
+	api := &fakeAPI{failures: failures}
+	api.server = httptest.NewServer(http.HandlerFunc(api.serve))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+// URL is the value of the api_url setting pointing to the fake
+func (a *fakeAPI) URL() string {
+	return a.server.URL
+}
+
+// Requests returns the routes requested to the services, without the
+// authentication requests
+func (a *fakeAPI) Requests() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string{}, a.requests...)
+}
+
+func (a *fakeAPI) serve(w http.ResponseWriter, r *http.Request) {
+	route := r.Method + " " + r.URL.Path
+	if r.URL.Path != fakeTokenPath && r.URL.Path != fakeCatalogPath {
+		a.mu.Lock()
+		a.requests = append(a.requests, route)
+		a.mu.Unlock()
+	}
+	if status, ok := a.failures[route]; ok {
+		writeFakeJSON(w, status, map[string]string{"message": http.StatusText(status)})
+		return
+	}
+	switch r.URL.Path {
+	case fakeTokenPath:
+		writeFakeJSON(w, http.StatusOK, map[string]string{
+			"token":      fakeToken,
+			"expires_at": fakeTokenExpiry,
+			"project_id": "fake-project",
+		})
+		return
+	case fakeCatalogPath:
+		a.serveCatalog(w)
+		return
+	}
+	if r.Header.Get("X-Auth-Token") != fakeToken {
+		writeFakeJSON(w, http.StatusUnauthorized, map[string]string{"message": "Invalid token"})
+		return
+	}
+	fixture := filepath.Join("testdata", "fakeapi", filepath.FromSlash(strings.Trim(r.URL.Path, "/")))
+	if r.Method != http.MethodGet {
+		fixture += "." + r.Method
+	}
+	b, err := os.ReadFile(fixture + ".json")
+	if err != nil {
+		writeFakeJSON(w, http.StatusNotFound, map[string]string{"message": "Not found"})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// serveCatalog lists the services of the fake in both regions
+func (a *fakeAPI) serveCatalog(w http.ResponseWriter) {
+	var services []map[string]interface{}
+	for _, region := range []string{"HN", "HCM"} {
+		for _, name := range fakeServices {
+			services = append(services, map[string]interface{}{
+				"name":           name,
+				"code":           name,
+				"canonical_name": name,
+				"region":         region,
+				"enabled":        true,
+				"service_url":    a.server.URL + "/" + name,
+			})
+		}
+	}
+	writeFakeJSON(w, http.StatusOK, map[string]interface{}{"services": services})
+}
+
+func writeFakeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, _ := json.Marshal(v)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
@@ -17,11 +17,10 @@ package cmd
 
 import (
 	"errors"
-	"fmt"
-	"log"
 	"strconv"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -43,7 +42,7 @@ var firewallCmd = &cobra.Command{
 	Short: "Bizfly Cloud Firewall Interaction",
 	Long:  "Bizfly Cloud Firewall Action: Create, List, Delete, Update, Remove Server from Firewall",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("firewall called")
+		logging.Debugf("firewall called")
 	},
 }
 
@@ -53,18 +52,21 @@ var firewallListCmd = &cobra.Command{
 	Long: `List all firewalls of your account in a region
 Example: bizfly firewall list
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		firewalls, err := client.CloudServer.Firewalls().List(ctx, &gobizfly.ListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, firewall := range firewalls {
 			fw := []string{firewall.ID, firewall.Name, firewall.Description, strconv.Itoa(firewall.RulesCount), strconv.Itoa(firewall.ServersCount), firewall.CreatedAt}
 			data = append(data, fw)
 		}
-		formatter.Output(firewallListHeader, data)
+		return formatter.Output(firewallListHeader, data, firewalls)
 	},
 }
 
@@ -77,20 +79,27 @@ Example: bizfly firewall delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 You can delete multiple firewalls with list of firewall id
 Example: bizfly firewall delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58 f5869e9c-9ab2-11ea-b9e3-e353a4f04836
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
-		for _, fwID := range args {
-			fmt.Printf("Deleting firewall %s \n", fwID)
-			_, err := client.CloudServer.Firewalls().Delete(ctx, fwID)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			fwID, err := resolveFirewall(ctx, client, arg)
+			if err != nil {
+				return err
+			}
+			logging.Infof("Deleting firewall %s", fwID)
+			_, err = client.CloudServer.Firewalls().Delete(ctx, fwID)
 			if err != nil {
 				if errors.Is(err, gobizfly.ErrNotFound) {
-					fmt.Printf("Firewall %s is not found", serverID)
-					return
+					return notFoundError("Firewall %s is not found", fwID)
 				} else {
-					log.Fatal(err)
+					return err
 				}
 			}
 		}
+		return nil
 	},
 }
 
@@ -102,18 +111,24 @@ var firewallServerList = &cobra.Command{
 	Long: `List applied servers with the firewall
 Example: bizfly firewall server list  02b28284-5a18-4a0e-9ecc-d5d1acaf7e7b
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("You need to specify firewall ID in the command")
+			return usageError("You need to specify firewall ID in the command")
 		}
-		client, ctx := getApiClient(cmd)
-		firewall, err := client.CloudServer.Firewalls().Get(ctx, args[0])
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		fwID, err := resolveFirewall(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		firewall, err := client.CloudServer.Firewalls().Get(ctx, fwID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Firewall %s is not found", serverID)
-				return
+				return notFoundError("Firewall %s is not found", args[0])
 			} else {
-				log.Fatal(err)
+				return err
 			}
 		}
 		var data [][]string
@@ -121,7 +136,7 @@ Example: bizfly firewall server list  02b28284-5a18-4a0e-9ecc-d5d1acaf7e7b
 			fw := []string{server.ID, server.Name, firewall.ID}
 			data = append(data, fw)
 		}
-		formatter.Output(firewallAppliedServersHeader, data)
+		return formatter.Output(firewallAppliedServersHeader, data, firewall)
 	},
 }
 
@@ -131,25 +146,36 @@ var firewallServerRemove = &cobra.Command{
 	Long: `Remove server from a firewall
 Example: bizfly firewall server remove <firewall ID> <server ID 1> <server ID 2> ..
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println(args)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logging.Debugf("Arguments: %v", args)
 		if len(args) < 2 {
-			log.Fatal("You need to specify firewall ID and server ID in the command")
+			return usageError("You need to specify firewall ID and server ID in the command")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		fwID, err := resolveFirewall(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		serverIDs, err := resolveAll(ctx, client, args[1:], resolveServer)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		frsr := gobizfly.FirewallRemoveServerRequest{
-			Servers: args[1:],
+			Servers: serverIDs,
 		}
-		_, err := client.CloudServer.Firewalls().RemoveServer(ctx, args[0], &frsr)
+		_, err = client.CloudServer.Firewalls().RemoveServer(ctx, fwID, &frsr)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Firewall %s is not found", serverID)
-				return
+				return notFoundError("Firewall %s is not found", args[0])
 			} else {
-				log.Fatal(err)
+				return err
 			}
 		}
-		fmt.Println("Removed servers from a fitirewall completed")
+		logging.Infof("Removed servers from a fitirewall completed")
+		return nil
 	},
 }
 
@@ -159,16 +185,19 @@ var firewallCreateCmd = &cobra.Command{
 	Long: `Create a new firewall in your account
 Example:
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		firewall, err := client.CloudServer.Firewalls().Create(ctx, &gobizfly.FirewallRequestPayload{Name: fwName})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		fw := []string{firewall.ID, firewall.Name, firewall.Description, strconv.Itoa(firewall.RulesCount), strconv.Itoa(firewall.ServersCount), firewall.CreatedAt}
 		data = append(data, fw)
-		formatter.Output(firewallListHeader, data)
+		return formatter.Output(firewallListHeader, data, firewall)
 	},
 }
 
@@ -182,18 +211,24 @@ var firewallRuleListCmd = &cobra.Command{
 	Long: `List all rules in the firewall
 Example: bizfly firewall rule list <firwall id>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("You need to specify firewall ID in the command")
+			return usageError("You need to specify firewall ID in the command")
 		}
-		client, ctx := getApiClient(cmd)
-		firewall, err := client.CloudServer.Firewalls().Get(ctx, args[0])
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		fwID, err := resolveFirewall(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		firewall, err := client.CloudServer.Firewalls().Get(ctx, fwID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Firewall %s is not found", serverID)
-				return
+				return notFoundError("Firewall %s is not found", args[0])
 			} else {
-				log.Fatal(err)
+				return err
 			}
 		}
 		var data [][]string
@@ -203,7 +238,7 @@ Example: bizfly firewall rule list <firwall id>
 		for _, rule := range firewall.OutBound {
 			data = append(data, []string{rule.ID, rule.Description, rule.Direction, rule.Type, rule.EtherType, rule.Protocol, rule.CIDR, rule.PortRange, rule.RemoteIPPrefix})
 		}
-		formatter.Output(firewallRuleHeader, data)
+		return formatter.Output(firewallRuleHeader, data, firewall)
 	},
 }
 
@@ -213,25 +248,32 @@ var firewallRuleDeleteCmd = &cobra.Command{
 	Long: `Delete a rule in a firewall
 Example: bizfly firewall rule delete <firewall ID> <rule ID>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 2 {
-			log.Fatal("You need to specify firewall ID and rule ID in the command")
+			return usageError("You need to specify firewall ID and rule ID in the command")
 		}
-		client, ctx := getApiClient(cmd)
-		_, err := client.CloudServer.Firewalls().Get(ctx, args[0])
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		fwID, err := resolveFirewall(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		_, err = client.CloudServer.Firewalls().Get(ctx, fwID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Firewall %s is not found", serverID)
-				return
+				return notFoundError("Firewall %s is not found", args[0])
 			} else {
-				log.Fatal(err)
+				return err
 			}
 		}
 		resp, err := client.CloudServer.Firewalls().DeleteRule(ctx, args[1])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println(resp.Message)
+		logging.Infof("%s", resp.Message)
+		return nil
 	},
 }
 
@@ -241,14 +283,21 @@ var firewallRuleCreateCmd = &cobra.Command{
 	Long: `Create a new rule in your firewall
 Example: bizfly firewall rule create <firewall ID> --direction <ingress|egress> --protocol <tcp|udp> --port-range <port range> --cidr <CIDR>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("You need to specify the fireewall ID in the command")
+			return usageError("You need to specify the fireewall ID in the command")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		fwID, err := resolveFirewall(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		firewall, err := client.CloudServer.Firewalls().Get(ctx, args[0])
+		firewall, err := client.CloudServer.Firewalls().Get(ctx, fwID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var inBoundRules []gobizfly.FirewallRuleCreateRequest
 		var outBoundRules []gobizfly.FirewallRuleCreateRequest
@@ -286,11 +335,12 @@ Example: bizfly firewall rule create <firewall ID> --direction <ingress|egress>
 			OutBound: outBoundRules,
 		}
 		
-		_, err = client.CloudServer.Firewalls().Update(ctx, args[0], &payload)
+		_, err = client.CloudServer.Firewalls().Update(ctx, fwID, &payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Created new firewall rule successfully")
+		logging.Infof("Created new firewall rule successfully")
+		return nil
 	},
 }
 
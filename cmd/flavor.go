@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 	"regexp"
 	"strconv"
 
@@ -21,7 +20,8 @@ var flavorCmd = &cobra.Command{
 	Use:   "flavor",
 	Short: "Bizfly Cloud Flavor Interaction",
 	Long:  `Bizfly Cloud Flavor Action: List Flavors`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return nil
 	},
 }
 
@@ -33,15 +33,18 @@ var flavorListCmd = &cobra.Command{
 List all flavor of Bizfly Cloud.
 Use: bizfly flavor list
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		flavors, err := client.CloudServer.Flavors().List(ctx)
 		if err != nil {
-			fmt.Printf("List flavors error %v", err)
-			os.Exit(1)
+			return fmt.Errorf("List flavors error %w", err)
 		}
 		var data [][]string
 		var flavorName string
+		listed := flavors[:0]
 		for _, flavor := range flavors {
 			flavor.RAM = flavor.RAM / 1024
 			if category != "" && category != flavor.Category {
@@ -63,9 +66,9 @@ Use: bizfly flavor list
 			}
 			s := []string{flavor.ID, flavorName, strconv.Itoa(flavor.VCPUs), strconv.Itoa(flavor.RAM), flavor.Category}
 			data = append(data, s)
+			listed = append(listed, flavor)
 		}
-		formatter.Output(flavorListHeader, data)
-
+		return formatter.Output(flavorListHeader, data, listed)
 	},
 }
 
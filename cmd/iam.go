@@ -5,6 +5,7 @@ import (
 	"strconv"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/spf13/cobra"
 	"github.com/bizflycloud/gobizfly"
 )
@@ -16,7 +17,7 @@ var iamCmd = &cobra.Command{
 	Short: "Bizfly Cloud IAM Interaction",
 	Long:  `Bizfly Cloud IAM Action: List Projects`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("iam called")
+		logging.Debugf("iam called")
 	},
 }
 
@@ -25,7 +26,7 @@ var projectsCmd = &cobra.Command{
 	Short: "Bizfly Cloud Projects Interaction",
 	Long:  `Bizfly Cloud Projects Action: List Projects`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("projects called")
+		logging.Debugf("projects called")
 	},
 }
 var projectsListCmd = &cobra.Command{
@@ -35,11 +36,14 @@ var projectsListCmd = &cobra.Command{
 List all projects in Bizfly Cloud
 Use: bizfly projects list
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		projects, err := client.IAM.ListProjects(ctx, gobizfly.ListProjectsOpts{})
 		if err != nil {
-			fmt.Printf("List projects error: %v", err)
+			return fmt.Errorf("List projects error: %w", err)
 		}
 		var data [][]string
 		for _, project := range projects {
@@ -47,7 +51,7 @@ Use: bizfly projects list
 				project.CreatedAt, project.UpdatedAt}
 			data = append(data, s)
 		}
-		formatter.Output(projectListHeader, data)
+		return formatter.Output(projectListHeader, data, projects)
 	},
 }
 
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-
 	"github.com/bizflycloud/bizflyctl/formatter"
 	"github.com/spf13/cobra"
 )
@@ -14,7 +13,8 @@ var imageCmd = &cobra.Command{
 	Use:   "image",
 	Short: "Bizfly Cloud Image Interaction",
 	Long:  `Bizfly Cloud Image Action: List OS Image, Create a custom image`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return nil
 	},
 }
 
@@ -26,11 +26,14 @@ var imageListCmd = &cobra.Command{
 List all os images in Bizfly Cloud
 Use: bizfly image list
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		images, err := client.CloudServer.OSImages().List(ctx)
 		if err != nil {
-			fmt.Printf("List os image error: %v", err)
+			return fmt.Errorf("List os image error: %w", err)
 		}
 		var data [][]string
 		for _, image := range images {
@@ -40,8 +43,7 @@ Use: bizfly image list
 				data = append(data, s)
 			}
 		}
-		formatter.Output(imageListHeader, data)
-
+		return formatter.Output(imageListHeader, data, images)
 	},
 }
 
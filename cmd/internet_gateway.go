@@ -17,11 +17,10 @@ limitations under the License.
 package cmd
 
 import (
-	"fmt"
-	"log"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -41,7 +40,7 @@ var internetGatewayCmd = &cobra.Command{
 	Short: "Bizfly Cloud Internet Gateway Interaction",
 	Long:  `Bizfly Cloud Internet Gateway Interaction: Create, List, Get, Update, Delete`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Internet Gateway called")
+		logging.Debugf("Internet Gateway called")
 	},
 }
 
@@ -66,8 +65,11 @@ func parseIGWResult(igw *gobizfly.ExtendedInternetGateway) []string {
 var internetGatewayListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List Internet Gateways",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		detailed := true
 		opts := gobizfly.ListInternetGatewayOpts{
 			Detailed: &detailed,
@@ -77,41 +79,47 @@ var internetGatewayListCmd = &cobra.Command{
 		}
 		igws, err := client.CloudServer.InternetGateways().List(ctx, opts)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		for _, igw := range igws.InternetGateways {
 			data = append(data, parseIGWResult(igw))
 		}
-		formatter.Output(internetGatewayHeaders, data)
+		return formatter.Output(internetGatewayHeaders, data, igws)
 	},
 }
 
 var internetGatewayGetCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get Internet Gateway",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			log.Fatal("Please provide Internet Gateway ID")
+			return usageError("Please provide Internet Gateway ID")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		igw, err := client.CloudServer.InternetGateways().Get(ctx, args[0])
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, parseIGWResult(igw))
-		formatter.Output(internetGatewayHeaders, data)
+		return formatter.Output(internetGatewayHeaders, data, igw)
 	},
 }
 
 var internetGatewayCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create Internet Gateway",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if igwName == "" {
-			log.Fatal("Please provide Internet Gateway name")
+			return usageError("Please provide Internet Gateway name")
 		}
 		payload := gobizfly.CreateInternetGatewayPayload{
 			Name:       igwName,
@@ -125,26 +133,29 @@ var internetGatewayCreateCmd = &cobra.Command{
 		}
 		igw, err := client.CloudServer.InternetGateways().Create(ctx, payload)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, parseIGWResult(igw))
-		formatter.Output(internetGatewayHeaders, data)
+		return formatter.Output(internetGatewayHeaders, data, igw)
 	},
 }
 
 var internetGatewayUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update Internet Gateway",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			log.Fatal("Please provide Internet Gateway ID")
+			return usageError("Please provide Internet Gateway ID")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		igwID := args[0]
 		oldIGW, err := client.CloudServer.InternetGateways().Get(ctx, igwID)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		oldNetworkIDs := []string{}
 		for _, network := range oldIGW.InterfacesInfo {
@@ -169,27 +180,31 @@ var internetGatewayUpdateCmd = &cobra.Command{
 		}
 		igw, err := client.CloudServer.InternetGateways().Update(ctx, args[0], payload)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, parseIGWResult(igw))
-		formatter.Output(internetGatewayHeaders, data)
+		return formatter.Output(internetGatewayHeaders, data, igw)
 	},
 }
 
 var internetGatewayDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete Internet Gateway",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			log.Fatal("Please provide Internet Gateway ID")
+			return usageError("Please provide Internet Gateway ID")
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.CloudServer.InternetGateways().Delete(ctx, args[0])
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
-		fmt.Println("Internet Gateway deleted successfully")
+		err = client.CloudServer.InternetGateways().Delete(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		logging.Infof("Internet Gateway deleted successfully")
+		return nil
 	},
 }
 
@@ -198,15 +213,18 @@ var internetGatewayDetachVPCCmd = &cobra.Command{
 	Short: "Detach VPC out of Internet Gateway",
 	Long: `Detach VPC out of Internet Gateway by setting network IDs to empty.
 Usage: ./bizfly internet-gateway detach-vpc <internet-gateway-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) == 0 {
-			log.Fatal("Please provide Internet Gateway ID")
+			return usageError("Please provide Internet Gateway ID")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		igwID := args[0]
 		oldIGW, err := client.CloudServer.InternetGateways().Get(ctx, igwID)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		payload := gobizfly.UpdateInternetGatewayPayload{
 			Name:        oldIGW.Name,
@@ -215,11 +233,11 @@ Usage: ./bizfly internet-gateway detach-vpc <internet-gateway-id>`,
 		}
 		igw, err := client.CloudServer.InternetGateways().Update(ctx, igwID, payload)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, parseIGWResult(igw))
-		formatter.Output(internetGatewayHeaders, data)
+		return formatter.Output(internetGatewayHeaders, data, igw)
 	},
 }
 
@@ -18,12 +18,11 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -71,17 +70,20 @@ var kafkaClusterListCmd = &cobra.Command{
 	Short: "List all cluster in your account",
 	Long:  `List all cluster in your account
 	Example: bizfly kafka clusters list`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		clusters, err := client.Kafka.List(ctx, &gobizfly.KafkaClusterListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, cluster := range clusters {
 			data = append(data, []string{cluster.ID, cluster.Name, cluster.AvailabilityZone, strconv.Itoa(cluster.Nodes), fmt.Sprintf("%d GB", cluster.VolumeSize), strconv.FormatBool(cluster.PublicAccess), cluster.Status, cluster.Flavor, cluster.CreatedAt})
 		}
-		formatter.Output(kafkaListClusterHeader, data)
+		return formatter.Output(kafkaListClusterHeader, data, clusters)
 	},
 }
 
@@ -91,19 +93,21 @@ var kafkaClusterGetCmd = &cobra.Command{
 	Short: "Get a kafka cluster",
 	Long: `Get detail a kafka cluster with kafka cluster ID as input
 	Example: bizfly kafka clusters get fd554aac-9ab1-11ea-b09d-bbaf82f02f58`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 
 		cluster, err := client.Kafka.Get(ctx, args[0])
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Cluster %s not found.", args[0])
-				return
+				return notFoundError("Cluster %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, node := range cluster.Nodes {
@@ -119,7 +123,7 @@ var kafkaClusterGetCmd = &cobra.Command{
 			WanIPAddrs := strings.Join(WanIP, ", ")
 			data = append(data, []string{node.ID, node.Name, node.AvailabilityZone, cluster.Flavor, fmt.Sprintf("%f GB/ %d GB", node.Used, node.VolumeSize), LanIPAddrs, WanIPAddrs, cluster.Status, cluster.CreatedAt})
 		}
-		formatter.Output(kafkaDetailClusterHeader, data)
+		return formatter.Output(kafkaDetailClusterHeader, data, cluster)
 	},
 }
 
@@ -128,7 +132,7 @@ var kafkaClusterCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a kafka cluster",
 	Long:  "Create a new kafka cluster, return a task ID of the processing.\nUse: bizfly kafka clusters create --name <cluster-name> --version <kafka-version-id> --vpc-network-id <vpc-network-id> --nodes <number-of-nodes> --volume-size <volume-size-in-GB> --flavor <flavor-name> [--public-access true|false] [--availability-zone <availability-zone>]",
-	Run: func(cmd *cobra.Command, arg []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		scr := gobizfly.KafkaInitClusterRequest{
 			ClusterName:      clusterName,
 			VersionID:        kafkaVersion,
@@ -139,15 +143,26 @@ var kafkaClusterCreateCmd = &cobra.Command{
 			Flavor:           kafkaFlavor,
 			AvailabilityZone: kafkaAvailabilityZone,
 		}
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		var existing map[string]bool
+		if waitEnabled {
+			// the task of create cannot be polled, the new cluster is found by
+			// its name among the clusters which did not exist before
+			if existing, err = kafkaClusterIDs(ctx, client, clusterName); err != nil {
+				return err
+			}
+		}
 		res, err := client.Kafka.Create(ctx, &scr)
 		if err != nil {
-			fmt.Printf("Create cluster error: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Create cluster error: %w", err)
 		}
-		fmt.Printf("Creating cluster: %#v\n", res)
-
-		fmt.Printf("Creating cluster with task id: %v\n", res.TaskID)
+		if err := formatter.Output(taskHeader, [][]string{{res.TaskID}}, res); err != nil {
+			return err
+		}
+		return waitForStatus(ctx, "Kafka cluster "+clusterName, kafkaCreatedStatus(client, clusterName, existing), kafkaClusterReadyStatus...)
 	},
 }
 
@@ -161,39 +176,39 @@ var kafkaClusterResizeCmd = &cobra.Command{
 		or
 		bizfly kafka clusters resize <kafka-cluster-id> --type volume --volume-size <size in GB>
 		`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify kafka-cluster-id in the command. Use bizfly kafka resize <kafka-cluster-id> --type <flavor|volume> --flavor or --volume-size")
-			os.Exit(1)
+			return usageError("You need to specify kafka-cluster-id in the command. Use bizfly kafka resize <kafka-cluster-id> --type <flavor|volume> --flavor or --volume-size")
 		}
 		resizeReq := &gobizfly.KafkaResizeClusterRequest{}
 		switch kafkaResizeType {
 		case "flavor":
 			if kafkaFlavor == "" {
-				fmt.Println("You need to specify --flavor when resizing type is flavor")
-				os.Exit(1)
+				return usageError("You need to specify --flavor when resizing type is flavor")
 			}
 			resizeReq.Type = "flavor"
 			resizeReq.Flavor = kafkaFlavor
 		case "volume":
 			if kafkaVolumeSize <= 0 {
-				fmt.Println("You need to specify --volume-size greater than 0 when resizing type is volume")
-				os.Exit(1)
+				return usageError("You need to specify --volume-size greater than 0 when resizing type is volume")
 			}
 			resizeReq.Type = "volume"
 			resizeReq.VolumeSize = kafkaVolumeSize
 		default:
-			fmt.Println("Invalid type. Use 'flavor' or 'volume'.")
-			os.Exit(1)
+			return usageError("Invalid type. Use 'flavor' or 'volume'.")
 		}
 		kafkaClusterID := args[0]
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		res, err := client.Kafka.Resize(ctx, kafkaClusterID, resizeReq)
 		if err != nil {
-			fmt.Printf("Resize cluster error %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("Resize cluster error %w", err)
 		}
-		fmt.Printf("Resizing cluster: %s\n", res.TaskID)
+		logging.Infof("Resizing cluster: %s", res.TaskID)
+		// the task of resize cannot be polled
+		return waitForUpdate(ctx, "Kafka cluster "+kafkaClusterID, kafkaResizeUpdate(client, kafkaClusterID, resizeReq), kafkaClusterReadyStatus...)
 	},
 }
 
@@ -205,24 +220,26 @@ var kafkaClusterAddNodeCmd = &cobra.Command{
 		Add node to a cluster.
 		Use: bizfly kafka clusters add-node <kafka-cluster-id> --nodes <number of nodes>
 		`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify kafka-cluster-id in the command. Use bizfly kafka add-node <kafka-cluster-id> --nodes")
-			os.Exit(1)
+			return usageError("You need to specify kafka-cluster-id in the command. Use bizfly kafka add-node <kafka-cluster-id> --nodes")
 		}
 		kafkaClusterID := args[0]
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		reqBody := &gobizfly.KafkaAddNodeRequest{
 			Nodes: nodes,
 			Type:  "increase",
 		}
 		res, err := client.Kafka.AddNode(ctx, kafkaClusterID, reqBody)
 		if err != nil {
-			fmt.Printf("Add node error %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("Add node error %w", err)
 		}
 
-		fmt.Printf("Adding node to cluster with task id: %s\n", res.TaskID)
+		logging.Infof("Adding node to cluster with task id: %s", res.TaskID)
+		return nil
 	},
 }
 
@@ -233,34 +250,33 @@ var kafkaClusterDeleteCmd = &cobra.Command{
 	Long: `Delete Kafka Cluster with kafka ID as input
 	Example: bizfly kafka clusters delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		_, err := client.Kafka.Get(ctx, args[0])
+		_, err = client.Kafka.Get(ctx, args[0])
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Kafka cluster %s is not found", args[0])
-				os.Exit(1)
+				return notFoundError("Kafka cluster %s is not found", args[0])
 			} else {
-				fmt.Printf("Error when get kafka cluster info: %v", err)
-				return
+				return fmt.Errorf("Error when get kafka cluster info: %w", err)
 			}
 		}
 		task, err := client.Kafka.Delete(ctx, args[0])
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Kafka cluster %s is not found", args[0])
-				os.Exit(1)
+				return notFoundError("Kafka cluster %s is not found", args[0])
 			} else {
-				fmt.Printf("Error when delete kafka cluster %v", err)
-				os.Exit(1)
+				return fmt.Errorf("Error when delete kafka cluster %w", err)
 			}
 		}
-		fmt.Printf("Deleting kafka cluster with task id: %s\n", task.TaskID)
+		logging.Infof("Deleting kafka cluster with task id: %s", task.TaskID)
+		return nil
 	},
 }
 
@@ -277,18 +293,20 @@ var kafkaFlavorListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all Kafka flavors",
 	Long:  `List all available Kafka flavors.\nUse: bizfly kafka flavors list`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		flavors, err := client.Kafka.ListFlavor(ctx, nil)
 		if err != nil {
-			fmt.Printf("Error listing Kafka flavors: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("Error listing Kafka flavors: %w", err)
 		}
 		var data [][]string
 		for _, flavor := range flavors {
 			data = append(data, []string{flavor.ID, flavor.Name, strings.Join([]string{strconv.Itoa(flavor.VCPUs), "Core(s)"}, " "), strings.Join([]string{strconv.Itoa(flavor.RAM), "MB"}, " "), strings.Join([]string{strconv.Itoa(flavor.Disk), "GB"}, " "), flavor.FlavorType})
 		}
-		formatter.Output(kafkaListFlavorHeader, data)
+		return formatter.Output(kafkaListFlavorHeader, data, flavors)
 	},
 }
 
@@ -305,18 +323,20 @@ var kafkaVersionListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all Kafka versions",
 	Long:  `List all available Kafka versions.\nUse: bizfly kafka versions list`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		versions, err := client.Kafka.ListVersion(ctx, nil)
 		if err != nil {
-			fmt.Printf("Error listing Kafka versions: %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("Error listing Kafka versions: %w", err)
 		}
 		var data [][]string
 		for _, version := range versions {
 			data = append(data, []string{version.ID, version.Name, version.Code, strconv.FormatBool(version.IsDefault)})
 		}
-		formatter.Output(kafkaListVersionHeader, data)
+		return formatter.Output(kafkaListVersionHeader, data, versions)
 	},
 }
 
@@ -340,6 +360,7 @@ func init() {
 	_ = kafkaClusterCreateCmd.MarkFlagRequired("flavor")
 	_ = kafkaClusterCreateCmd.MarkFlagRequired("volume-size")
 	_ = kafkaClusterCreateCmd.MarkFlagRequired("vpc-network-id")
+	addWaitFlags(kafkaClusterCreateCmd)
 	kafkaClusterCmd.AddCommand(kafkaClusterCreateCmd)
 
 	// required one of --flavor or --volume-size
@@ -347,6 +368,7 @@ func init() {
 	_ = kafkaClusterResizeCmd.MarkFlagRequired("type")
 	kafkaClusterResizeCmd.Flags().StringVar(&kafkaFlavor, "flavor", "", "New flavor for resizing.\nUse 'bizfly kafka flavors list' to see available flavors")
 	kafkaClusterResizeCmd.Flags().IntVar(&kafkaVolumeSize, "volume-size", 0, "New volume size for resizing")
+	addWaitFlags(kafkaClusterResizeCmd)
 	kafkaClusterCmd.AddCommand(kafkaClusterResizeCmd)
 
 	kafkaClusterCmd.AddCommand(kafkaClusterAddNodeCmd)
@@ -18,7 +18,6 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -30,6 +29,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 )
 
@@ -91,11 +91,14 @@ var clusterList = &cobra.Command{
 	Use:   "list",
 	Short: "List your Kubernetes cluster",
 	Long:  "List your Kubernetes cluster",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		clusters, err := client.KubernetesEngine.List(ctx, &gobizfly.ListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, cluster := range clusters {
@@ -104,7 +107,7 @@ var clusterList = &cobra.Command{
 				cluster.ClusterStatus, strings.Join(cluster.Tags, ", "), cluster.CreatedAt, cluster.Version.K8SVersion,
 			})
 		}
-		formatter.Output(kubernetesClusterHeader, data)
+		return formatter.Output(kubernetesClusterHeader, data, clusters)
 	},
 }
 
@@ -114,48 +117,67 @@ var clusterCreate = &cobra.Command{
 	Long: `Create Kubernetes cluster with worker pool using file or flags (Sample config file in example)
 - Using flag example: ./bizfly kubernetes create --name test_cli --version 5f7d3a91d857155ad4993a32 --vpc-network-id 145bed1f-a7f7-4f88-ab3d-ce2fc95a4e71 -tag abc -tag xyz --worker-pool "name=testworkerpool;flavor=nix.3c_6g;profile_type=premium;volume_type=PREMIUM-HDD1;volume_size=40;availability_zone=HN1;desired_size=1;min_size=1;max_size=10;labels=env=dev;taints=app=demo:NoSchedule"
 - Using config file example: ./bizfly kubernetes create --config-file create_cluster.yml`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		var data [][]string
+		var clusterID string
 		if inputConfigFile != "" {
 			fileBytes, err := os.ReadFile(inputConfigFile)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			var ccr *gobizfly.ClusterCreateRequest
 			if err := yaml.Unmarshal(fileBytes, &ccr); err != nil {
-				log.Fatal(err)
+				return err
 			}
 			cluster, err := client.KubernetesEngine.Create(ctx, ccr)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			data = append(data, []string{
 				cluster.UID, cluster.Name, cluster.VPCNetworkID, strconv.Itoa(cluster.WorkerPoolsCount),
 				cluster.ClusterStatus, strings.Join(cluster.Tags, ", "), cluster.CreatedAt, cluster.Version.K8SVersion,
 			})
-			formatter.Output(kubernetesClusterHeader, data)
+			if err := formatter.Output(kubernetesClusterHeader, data, cluster); err != nil {
+				return err
+			}
+			clusterID = cluster.UID
 		} else {
 			workerPoolObjs := make([]gobizfly.WorkerPool, 0)
 			for _, pool := range workerPools {
-				workerPoolObjs = append(workerPoolObjs, parseWorkerPool(pool))
+				workerPoolObj, err := parseWorkerPool(pool)
+				if err != nil {
+					return err
+				}
+				workerPoolObjs = append(workerPoolObjs, workerPoolObj)
+			}
+			vpcID, err := resolveVPC(ctx, client, vpcNetworkID)
+			if err != nil {
+				return err
 			}
 			cluster, err := client.KubernetesEngine.Create(ctx, &gobizfly.ClusterCreateRequest{
 				Name:         clusterName,
 				Version:      clusterVersion,
-				VPCNetworkID: vpcNetworkID,
+				VPCNetworkID: vpcID,
 				WorkerPools:  workerPoolObjs,
 				Tags:         tags,
 			})
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			data = append(data, []string{
 				cluster.UID, cluster.Name, cluster.VPCNetworkID, strconv.Itoa(cluster.WorkerPoolsCount),
 				cluster.ClusterStatus, strings.Join(cluster.Tags, ", "), cluster.CreatedAt, cluster.Version.K8SVersion,
 			})
-			formatter.Output(kubernetesClusterHeader, data)
+			if err := formatter.Output(kubernetesClusterHeader, data, cluster); err != nil {
+				return err
+			}
+			clusterID = cluster.UID
 		}
+		return waitForStatus(ctx, "Cluster "+clusterID, clusterStatus(client, clusterID), clusterReadyStatus...)
 	},
 }
 
@@ -165,16 +187,22 @@ var clusterGet = &cobra.Command{
 	Long: `Get detail of cluster. 
 - Using example: bizfly kubernetes get <cluster id>
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		cluster, err := client.KubernetesEngine.Get(ctx, args[0])
+		clusterID, err := resolveCluster(ctx, client, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		cluster, err := client.KubernetesEngine.Get(ctx, clusterID)
+		if err != nil {
+			return err
 		}
 		var data [][]string
 		var workerPoolIds []string
@@ -185,7 +213,7 @@ var clusterGet = &cobra.Command{
 			cluster.UID, cluster.Name, cluster.VPCNetworkID, strings.Join(workerPoolIds, "\n"), strconv.Itoa(cluster.WorkerPoolsCount),
 			cluster.ClusterStatus, strings.Join(cluster.Tags, ", "), cluster.CreatedAt, cluster.Version.K8SVersion,
 		})
-		formatter.Output(detailKubernetesCluster, data)
+		return formatter.Output(detailKubernetesCluster, data, cluster)
 	},
 }
 
@@ -195,18 +223,36 @@ var clusterDelete = &cobra.Command{
 	Long: `Delete a kubernetes cluster and all worker pools
 - Using example: bizfly kubernetes delete <cluster id>
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		clusterID, err := resolveCluster(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.KubernetesEngine.Delete(ctx, args[0])
+		cluster, err := client.KubernetesEngine.Get(ctx, clusterID)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		details := []string{fmt.Sprintf("Cluster %s (%s)", cluster.Name, cluster.UID)}
+		for _, workerPool := range cluster.WorkerPools {
+			details = append(details, fmt.Sprintf("  worker pool %s: %d node(s) of %s", workerPool.Name, workerPool.DesiredSize, workerPool.Flavor))
 		}
-		fmt.Printf("Cluster is in the process of being deleted")
+		if err := confirmDestroy("The cluster and all its worker pools will be deleted:", details); err != nil {
+			return err
+		}
+		err = client.KubernetesEngine.Delete(ctx, clusterID)
+		if err != nil {
+			return err
+		}
+		logging.Infof("Cluster is in the process of being deleted")
+		return waitForDeletion(ctx, "Cluster "+clusterID, clusterStatus(client, clusterID))
 	},
 }
 
@@ -216,26 +262,32 @@ var addWorkerPool = &cobra.Command{
 	Long: `Add Kubernetes worker pool using file or flags (Sample config file in example)
 - Using flag example: ./bizfly kubernetes workerpool add xfbxsws38dcs8o94 --worker-pool name=testworkerpool;flavor=nix.3c_6g;profile_type=premium;volume_type=PREMIUM-HDD1;volume_size=40;availability_zone=HN1;desired_size=1;min_size=1;max_size=10;labels=env=dev;taints=app=demo:NoSchedule
 - Using config file example: ./bizfly kubernetes add-workerpool 55viixy9ma6yaiwu --config-file add_pools.yml`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		clusterID, err := resolveCluster(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		var data [][]string
 		if inputConfigFile != "" {
 			fileBytes, err := os.ReadFile(inputConfigFile)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			var awpr *gobizfly.AddWorkerPoolsRequest
 			if err := yaml.Unmarshal(fileBytes, &awpr); err != nil {
-				log.Fatal(err)
+				return err
 			}
-			workerPools, err := client.KubernetesEngine.AddWorkerPools(ctx, args[0], awpr)
+			workerPools, err := client.KubernetesEngine.AddWorkerPools(ctx, clusterID, awpr)
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			for _, workerPool := range workerPools {
 				data = append(data, []string{
@@ -244,18 +296,23 @@ var addWorkerPool = &cobra.Command{
 					strconv.Itoa(workerPool.MinSize), strconv.Itoa(workerPool.MaxSize), workerPool.CreatedAt,
 				})
 			}
-			formatter.Output(kubernetesWorkerPoolHeader, data)
-
+			if err := formatter.Output(kubernetesWorkerPoolHeader, data, workerPools); err != nil {
+				return err
+			}
 		} else {
 			workerPoolObjs := make([]gobizfly.WorkerPool, 0)
 			for _, pool := range workerPools {
-				workerPoolObjs = append(workerPoolObjs, parseWorkerPool(pool))
+				workerPoolObj, err := parseWorkerPool(pool)
+				if err != nil {
+					return err
+				}
+				workerPoolObjs = append(workerPoolObjs, workerPoolObj)
 			}
-			workerPools, err := client.KubernetesEngine.AddWorkerPools(ctx, args[0], &gobizfly.AddWorkerPoolsRequest{
+			workerPools, err := client.KubernetesEngine.AddWorkerPools(ctx, clusterID, &gobizfly.AddWorkerPoolsRequest{
 				WorkerPools: workerPoolObjs,
 			})
 			if err != nil {
-				log.Fatal(err)
+				return err
 			}
 			for _, workerPool := range workerPools {
 				data = append(data, []string{
@@ -264,9 +321,12 @@ var addWorkerPool = &cobra.Command{
 					strconv.Itoa(workerPool.MinSize), strconv.Itoa(workerPool.MaxSize), workerPool.CreatedAt,
 				})
 
-				formatter.Output(kubernetesWorkerPoolHeader, data)
+				if err := formatter.Output(kubernetesWorkerPoolHeader, data, workerPools); err != nil {
+					return err
+				}
 			}
 		}
+		return nil
 	},
 }
 
@@ -276,18 +336,25 @@ var recycleNode = &cobra.Command{
 	Long: `Recycle a node in a worker pool in a cluster 
 Using example: bizfly kubernetes workerpool node recycle <cluster id> <workerpool id> <node id>
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 3 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.KubernetesEngine.RecycleNode(ctx, args[0], args[1], args[2])
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Recycling node successfully")
+		clusterID, err := resolveCluster(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		err = client.KubernetesEngine.RecycleNode(ctx, clusterID, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		logging.Infof("Recycling node successfully")
+		return nil
 	},
 }
 
@@ -297,18 +364,25 @@ var deleteWorkerPool = &cobra.Command{
 	Long: `Delete a worker pool in a kubernetes cluster
 - Using example: bizfly kubernetes workerpool delete <cluster id> <worker pool id>
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 2 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.KubernetesEngine.DeleteClusterWorkerPool(ctx, args[0], args[1])
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Worker pool is deleting now")
+		clusterID, err := resolveCluster(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		err = client.KubernetesEngine.DeleteClusterWorkerPool(ctx, clusterID, args[1])
+		if err != nil {
+			return err
+		}
+		logging.Infof("Worker pool is deleting now")
+		return nil
 	},
 }
 
@@ -318,16 +392,22 @@ var getWorkerPool = &cobra.Command{
 	Long: `Get detail of worker pool in a kubernetes cluster
 - Using example: bizfly kubernetes workerpool get <cluster id> <worker pool id>
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 2 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
 		}
-		client, ctx := getApiClient(cmd)
-		workerPool, err := client.KubernetesEngine.GetClusterWorkerPool(ctx, args[0], args[1])
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		clusterID, err := resolveCluster(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		workerPool, err := client.KubernetesEngine.GetClusterWorkerPool(ctx, clusterID, args[1])
+		if err != nil {
+			return err
 		}
 		var data [][]string
 		var nodes []string
@@ -339,7 +419,7 @@ var getWorkerPool = &cobra.Command{
 			strconv.Itoa(workerPool.VolumeSize), workerPool.VolumeType, strings.Join(nodes, "\n"), strconv.FormatBool(workerPool.EnableAutoScaling),
 			strconv.Itoa(workerPool.MinSize), strconv.Itoa(workerPool.MaxSize), workerPool.CreatedAt,
 		})
-		formatter.Output(kubernetesWorkerPoolHeader, data)
+		return formatter.Output(kubernetesWorkerPoolHeader, data, workerPool)
 	},
 }
 
@@ -349,25 +429,32 @@ var updateWorkerPool = &cobra.Command{
 	Long: `Update a worker pool in a cluster
 - Using example: bizfly kubnernetes worker pool update <cluster id> <workerpool id> --desired-size <size> --min-size <size> --max-size <size> --autoscaling <true|false>
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 2 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
 		}
 
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		clusterID, err := resolveCluster(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
 		uwr := &gobizfly.UpdateWorkerPoolRequest{
 			DesiredSize:       desiredSize,
 			EnableAutoScaling: enableAutoScaling,
 			MinSize:           minSize,
 			MaxSize:           maxSize,
 		}
-		err := client.KubernetesEngine.UpdateClusterWorkerPool(ctx, args[0], args[1], uwr)
+		err = client.KubernetesEngine.UpdateClusterWorkerPool(ctx, clusterID, args[1], uwr)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Worker pool is updating now")
+		logging.Infof("Worker pool is updating now")
+		return waitForUpdate(ctx, "Worker pool "+args[1], workerPoolSizeUpdate(client, clusterID, args[1], desiredSize), workerPoolReadyStatus...)
 	},
 }
 
@@ -377,42 +464,55 @@ var deleteWorkerPoolNode = &cobra.Command{
 	Long: `Delete a node in a worker pool in a cluster 
 Using example: bizfly kubernetes workerpool node delete <cluster id> <worker pool id> <node id>
 	`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 3 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.KubernetesEngine.DeleteClusterWorkerPoolNode(ctx, args[0], args[1], args[2])
+		clusterID, err := resolveCluster(ctx, client, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Worker pool is in the process of being deleted")
+		err = client.KubernetesEngine.DeleteClusterWorkerPoolNode(ctx, clusterID, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		logging.Infof("Worker pool is in the process of being deleted")
+		return nil
 	},
 }
 
 var getKubeConfig = &cobra.Command{
 	Use:   "get",
 	Short: "Get kubeconfig",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			fmt.Println("Invalid arguments")
 			_ = cmd.Help() // Display the help message
-			return
+			return usageError("Invalid arguments")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		clusterID, err := resolveCluster(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		kubeconfigOptions := &gobizfly.GetKubeConfigOptions{
 			ExpiteTime: expireTime,
 		}
-		resp, err := client.KubernetesEngine.GetKubeConfig(ctx, args[0], kubeconfigOptions)
+		resp, err := client.KubernetesEngine.GetKubeConfig(ctx, clusterID, kubeconfigOptions)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		currentDir, _ := os.Getwd()
 
-		defaultFileName := fmt.Sprintf("%s.kubeconfig", args[0])
+		defaultFileName := fmt.Sprintf("%s.kubeconfig", clusterID)
 
 		stat, err := os.Stat(outputKubeConfigFilePath)
 		if err == nil && stat.IsDir() {
@@ -425,9 +525,10 @@ var getKubeConfig = &cobra.Command{
 		file, _ := os.Create(outputKubeConfigFilePath)
 		_, err = file.WriteString(resp)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Get kubernetes config successfully. Output path:", outputKubeConfigFilePath)
+		logging.Infof("Get kubernetes config successfully. Output path: %s", outputKubeConfigFilePath)
+		return nil
 	},
 }
 
@@ -440,24 +541,24 @@ func isIntField(key string) bool {
 	return false
 }
 
-func parseTaints(pair string) []gobizfly.Taint {
+func parseTaints(pair string) ([]gobizfly.Taint, error) {
 	r := regexp.MustCompile("(.*)=(.*):(.*)")
 	rTaints := regexp.MustCompile(`taints=(.*)`)
 	subStrs := rTaints.FindStringSubmatch(pair)
 	if len(subStrs) == 0 {
-		log.Fatal("Invalid worker pool taints input")
+		return nil, usageError("Invalid worker pool taints input")
 	}
 	values := subStrs[1]
 	taintPairs := strings.Split(values, ",")
 	taints := make([]gobizfly.Taint, 0)
 	for _, taintPair := range taintPairs {
 		subStrs := r.FindStringSubmatch(taintPair)
-		fmt.Println(subStrs)
+		logging.Debugf("Taint: %v", subStrs)
 		if len(subStrs) == 0 {
-			log.Fatal("Invalid worker pool taints input")
+			return nil, usageError("Invalid worker pool taints input")
 		}
 		if subStrs[3] == "" || subStrs[1] == "" {
-			log.Fatal("Invalid worker pool taints input")
+			return nil, usageError("Invalid worker pool taints input")
 		}
 		taint := gobizfly.Taint{
 			Effect: subStrs[3],
@@ -466,15 +567,15 @@ func parseTaints(pair string) []gobizfly.Taint {
 		}
 		taints = append(taints, taint)
 	}
-	return taints
+	return taints, nil
 }
 
-func parseLabels(pair string) map[string]string {
+func parseLabels(pair string) (map[string]string, error) {
 	r := regexp.MustCompile("(.*)=(.*)")
 	rLabels := regexp.MustCompile(`labels=(.*)`)
 	subStrs := rLabels.FindStringSubmatch(pair)
 	if len(subStrs) == 0 {
-		log.Fatal("Invalid worker pool labels input")
+		return nil, usageError("Invalid worker pool labels input")
 	}
 	values := subStrs[1]
 	labelPairs := strings.Split(values, ",")
@@ -482,14 +583,14 @@ func parseLabels(pair string) map[string]string {
 	for _, labelPair := range labelPairs {
 		subStrs := r.FindStringSubmatch(labelPair)
 		if len(subStrs) == 0 {
-			log.Fatal("Invalid worker pool labels input")
+			return nil, usageError("Invalid worker pool labels input")
 		}
 		labelsMap[subStrs[1]] = subStrs[2]
 	}
-	return labelsMap
+	return labelsMap, nil
 }
 
-func parseWorkerPool(workerPoolStr string) gobizfly.WorkerPool {
+func parseWorkerPool(workerPoolStr string) (gobizfly.WorkerPool, error) {
 	pairs := strings.Split(workerPoolStr, ";")
 	strRequiredFields := []string{"name", "flavor", "profile_type", "volume_type", "availability_zone"}
 	intRequiredFields := []string{"volume_size", "desired_size", "min_size", "max_size"}
@@ -501,18 +602,26 @@ func parseWorkerPool(workerPoolStr string) gobizfly.WorkerPool {
 	r := regexp.MustCompile("(.*)=(.*)")
 	for _, pair := range pairs {
 		if strings.Contains(pair, "labels") {
-			mapFieldMap["labels"] = parseLabels(pair)
+			labels, err := parseLabels(pair)
+			if err != nil {
+				return gobizfly.WorkerPool{}, err
+			}
+			mapFieldMap["labels"] = labels
 			continue
 		}
 		if strings.Contains(pair, "taints") {
-			taintsField = parseTaints(pair)
+			taints, err := parseTaints(pair)
+			if err != nil {
+				return gobizfly.WorkerPool{}, err
+			}
+			taintsField = taints
 			continue
 		}
 		subStrs := r.FindStringSubmatch(pair)
 		if len(subStrs) == 0 {
-			log.Fatal("Invalid worker pool input")
+			return gobizfly.WorkerPool{}, usageError("Invalid worker pool input")
 		}
-		fmt.Println(subStrs, len(subStrs))
+		logging.Debugf("Worker pool field: %v", subStrs)
 		key, value := subStrs[1], subStrs[2]
 		if key == "enable_autoscaling" {
 			b, _ := strconv.ParseBool(value)
@@ -527,12 +636,12 @@ func parseWorkerPool(workerPoolStr string) gobizfly.WorkerPool {
 	}
 	for _, field := range strRequiredFields {
 		if strFieldMap[field] == "" {
-			log.Fatal("Missing required worker pool field: ", field)
+			return gobizfly.WorkerPool{}, usageError("Missing required worker pool field: %s", field)
 		}
 	}
 	for _, field := range intRequiredFields {
 		if intFieldMap[field] == 0 {
-			log.Fatal("Missing required worker pool field: ", field)
+			return gobizfly.WorkerPool{}, usageError("Missing required worker pool field: %s", field)
 		}
 	}
 	workerPool := gobizfly.WorkerPool{
@@ -549,8 +658,8 @@ func parseWorkerPool(workerPoolStr string) gobizfly.WorkerPool {
 		Labels:            mapFieldMap["labels"],
 		Taints:            taintsField,
 	}
-	fmt.Printf("WorkerPool %v+", workerPool)
-	return workerPool
+	logging.Debugf("WorkerPool %+v", workerPool)
+	return workerPool, nil
 }
 
 func init() {
@@ -560,6 +669,8 @@ func init() {
 	kubernetesCmd.AddCommand(kubernetesKubeConfigCmd)
 
 	kubernetesCmd.AddCommand(clusterList)
+	addConfirmFlag(clusterDelete)
+	addWaitFlags(clusterDelete)
 	kubernetesCmd.AddCommand(clusterDelete)
 	kubernetesCmd.AddCommand(clusterGet)
 	kubernetesWorkerPoolCmd.AddCommand(deleteWorkerPool)
@@ -579,6 +690,7 @@ func init() {
 	_ = clusterCreate.MarkFlagRequired("version")
 	_ = clusterCreate.MarkFlagRequired("vpc-network-id")
 	_ = clusterCreate.MarkFlagRequired("worker-pool")
+	addWaitFlags(clusterCreate)
 	kubernetesCmd.AddCommand(clusterCreate)
 
 	awp := addWorkerPool.PersistentFlags()
@@ -595,7 +707,7 @@ func init() {
 	_ = updateWorkerPool.MarkFlagRequired("desired-size")
 	_ = updateWorkerPool.MarkFlagRequired("min-size")
 	_ = updateWorkerPool.MarkFlagRequired("max-size")
-
+	addWaitFlags(updateWorkerPool)
 	kubernetesWorkerPoolCmd.AddCommand(updateWorkerPool)
 
 	getKubeConfig.PersistentFlags().StringVar(&outputKubeConfigFilePath, "output", ".", "Output path")
@@ -18,11 +18,11 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"log"
 	"strconv"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -65,7 +65,7 @@ var lbCmd = &cobra.Command{
 	Short: "Bizfly Cloud Load Balancer Interaction",
 	Long:  `Bizfly Cloud Load Balancer Action: Create, List, Delete`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("loadbalancer called")
+		logging.Debugf("loadbalancer called")
 	},
 }
 
@@ -88,7 +88,7 @@ var lbHealthMonitorCmd = &cobra.Command{
 	Short: "Bizfly Cloud Load Balancer Health Monitor Interaction",
 	Long:  "Bizfly Cloud Load Balancer Health Monitor Action: Create, List, Delete, Get",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("health-monitor called")
+		logging.Debugf("health-monitor called")
 	},
 }
 
@@ -98,14 +98,22 @@ var lbCreateCmd = &cobra.Command{
 	Short: "Create a load balancer",
 	Long: `Create a load balancer
 Example: bizflyctl loadbalancer create --name lb1 --type large --network-type external --listener 8080:8080 --listener 8443:8443 --pool-id pool1 --pool-id pool2 --health-monitor-id hm1`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		payload := gobizfly.LoadBalancerCreateRequest{
 			Name:         lbName,
 			Type:         lbType,
 			VPCNetworkID: vpcNetworkId,
 			NetworkType:  networkType,
 		}
+		if vpcNetworkId != "" {
+			if payload.VPCNetworkID, err = resolveVPC(ctx, client, vpcNetworkId); err != nil {
+				return err
+			}
+		}
 		if description != "" {
 			payload.Description = description
 		}
@@ -135,11 +143,14 @@ Example: bizflyctl loadbalancer create --name lb1 --type large --network-type ex
 
 		lb, err := client.CloudLoadBalancer.Create(ctx, &payload)
 		if err != nil {
-			log.Fatalf("Error creating load balancer: %v", err)
+			return fmt.Errorf("Error creating load balancer: %w", err)
 		}
 		var data [][]string
 		data = append(data, []string{lb.ID, lb.Name, lb.NetworkType, lb.VipAddress, lb.OperatingStatus, lb.Type})
-		formatter.Output(lbListHeader, data)
+		if err := formatter.Output(lbListHeader, data, lb); err != nil {
+			return err
+		}
+		return waitForStatus(ctx, "Load balancer "+lb.ID, loadBalancerStatus(client, lb.ID), loadBalancerReadyStatus...)
 	},
 }
 
@@ -153,19 +164,26 @@ Example: bizfly loadbalancer delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 You can delete multiple loadbalancers with list of loadbalancer id
 Example: bizfly loadbalancer delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58 f5869e9c-9ab2-11ea-b9e3-e353a4f04836
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
-		for _, lbID := range args {
-			fmt.Printf("Deleting load balancer %s \n", lbID)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			lbID, err := resolveLoadBalancer(ctx, client, arg)
+			if err != nil {
+				return err
+			}
+			logging.Infof("Deleting load balancer %s", lbID)
 			lbdr := gobizfly.LoadBalancerDeleteRequest{ID: lbID, Cascade: true}
-			err := client.CloudLoadBalancer.Delete(ctx, &lbdr)
+			err = client.CloudLoadBalancer.Delete(ctx, &lbdr)
 			if err != nil {
 				if errors.Is(err, gobizfly.ErrNotFound) {
-					fmt.Printf("Load Balancer %s is not found", lbID)
-					return
+					return notFoundError("Load Balancer %s is not found", lbID)
 				}
 			}
 		}
+		return nil
 	},
 }
 
@@ -174,18 +192,21 @@ var lbListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all load balancer in your account",
 	Long:  `List all load balancer in your account`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		lbs, err := client.CloudLoadBalancer.List(ctx, &gobizfly.ListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, lb := range lbs {
 			s := []string{lb.ID, lb.Name, lb.NetworkType, lb.VipAddress, lb.OperatingStatus, lb.Type}
 			data = append(data, s)
 		}
-		formatter.Output(lbListHeader, data)
+		return formatter.Output(lbListHeader, data, lbs)
 	},
 }
 
@@ -196,23 +217,29 @@ var lbGetCmd = &cobra.Command{
 	Long: `Get detail a load balancer with load balancer ID as input
 Example: bizfly loadbalancer get fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		lbID, err := resolveLoadBalancer(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 
-		lb, err := client.CloudLoadBalancer.Get(ctx, args[0])
+		lb, err := client.CloudLoadBalancer.Get(ctx, lbID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Load Balancer %s not found.", args[0])
-				return
+				return notFoundError("Load Balancer %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{lb.ID, lb.Name, lb.NetworkType, lb.VipAddress, lb.OperatingStatus, lb.Type})
-		formatter.Output(lbListHeader, data)
+		return formatter.Output(lbListHeader, data, lb)
 	},
 }
 
@@ -223,18 +250,21 @@ var lbPoolDeleteCmd = &cobra.Command{
 	Long: `Delete Pool in a Load balancer with Pool ID as input
 Example: bizfly loadbalancer pool delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		// TODO: check length of args
 		poolID := args[0]
-		fmt.Printf("Deleting pool %s \n", poolID)
-		err := client.CloudLoadBalancer.Pools().Delete(ctx, poolID)
+		logging.Infof("Deleting pool %s", poolID)
+		err = client.CloudLoadBalancer.Pools().Delete(ctx, poolID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Pool %s is not found", poolID)
-				return
+				return notFoundError("Pool %s is not found", poolID)
 			}
 		}
+		return nil
 	},
 }
 
@@ -245,20 +275,23 @@ var lbPoolListCmd = &cobra.Command{
 	Long: `List all pools in a load balancer
 Example: bizfly loadbalancer pool list <loadbalancer_id>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		// TODO Check length args
 		lbID := args[0]
 		pools, err := client.CloudLoadBalancer.Pools().List(ctx, lbID, &gobizfly.ListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, pool := range pools {
 			s := []string{pool.ID, pool.Name, pool.LBAlgorithm, pool.Protocol, pool.OperatingStatus}
 			data = append(data, s)
 		}
-		formatter.Output(poolListHeader, data)
+		return formatter.Output(poolListHeader, data, pools)
 	},
 }
 
@@ -268,10 +301,13 @@ var lbPoolCreateCmd = &cobra.Command{
 	Long: `Create a pool in a load balancer
 Example: bizfly loadbalancer pool create <loadbalancer_id> --name <pool_name> --protocol <protocol> --lb-algorithm <lb_algorithm>
 ...`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
 		}
 		payload := &gobizfly.CloudLoadBalancerPoolCreateRequest{
 			Name:        &poolName,
@@ -289,11 +325,11 @@ Example: bizfly loadbalancer pool create <loadbalancer_id> --name <pool_name> --
 		}
 		pool, err := client.CloudLoadBalancer.Pools().Create(ctx, args[0], payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{pool.ID, pool.Name, pool.LBAlgorithm, pool.Protocol, pool.OperatingStatus})
-		formatter.Output(poolListHeader, data)
+		return formatter.Output(poolListHeader, data, pool)
 	},
 }
 
@@ -302,10 +338,13 @@ var lbListenerUpdateCmd = &cobra.Command{
 	Short: "Update a listener in a load balancer",
 	Long: `Update a listener in a load balancer
 Example: bizfly loadbalancer listener update <loadbalancer_id> --name <listener_name> --protocol <protocol> --port <port>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
 		}
 		listener, err := client.CloudLoadBalancer.Listeners().Update(ctx, args[0], &gobizfly.CloudLoadBalancerListenerUpdateRequest{
 			Name:                   &listenerName,
@@ -314,11 +353,11 @@ Example: bizfly loadbalancer listener update <loadbalancer_id> --name <listener_
 			DefaultTLSContainerRef: &tlsRef,
 		})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{listener.ID, listener.Name, listener.Protocol, strconv.Itoa(listener.ProtocolPort), listener.OperatingStatus, listener.DefaultPoolID})
-		formatter.Output(listenerListHeader, data)
+		return formatter.Output(listenerListHeader, data, listener)
 	},
 }
 
@@ -329,23 +368,25 @@ var lbPoolGetCmd = &cobra.Command{
 	Long: `Get detail a pool in a load balancer with pool ID as input
 Example: bizfly loadbalancer pool get fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 
 		pool, err := client.CloudLoadBalancer.Pools().Get(ctx, args[0])
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Pool %s not found.", args[0])
-				return
+				return notFoundError("Pool %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{pool.ID, pool.Name, pool.LBAlgorithm, pool.Protocol, pool.OperatingStatus})
-		formatter.Output(poolListHeader, data)
+		return formatter.Output(poolListHeader, data, pool)
 	},
 }
 
@@ -354,10 +395,13 @@ var lbListenerCreateCmd = &cobra.Command{
 	Short: "Create a listener in a load balancer",
 	Long: `Create a listener in a load balancer
 Example: bizfly loadbalancer listener create <loadbalancer_id> --name <listener_name> --protocol <protocol> --port <port>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
 		}
 		listener, err := client.CloudLoadBalancer.Listeners().Create(ctx, args[0], &gobizfly.CloudLoadBalancerListenerCreateRequest{
 			Name:          &listenerName,
@@ -367,11 +411,11 @@ Example: bizfly loadbalancer listener create <loadbalancer_id> --name <listener_
 			DefaultPoolID: &defaultPoolID,
 		})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{listener.ID, listener.Name, listener.Protocol, strconv.Itoa(listener.ProtocolPort), listener.OperatingStatus, listener.DefaultPoolID})
-		formatter.Output(listenerListHeader, data)
+		return formatter.Output(listenerListHeader, data, listener)
 	},
 }
 
@@ -382,18 +426,21 @@ var lbListenerDeleteCmd = &cobra.Command{
 	Long: `Delete Listener in a Load balancer with Listener ID as input
 Example: bizfly loadbalancer listener delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		// TODO: check length of args
 		listenerID := args[0]
-		fmt.Printf("Deleting listener %s \n", listenerID)
-		err := client.CloudLoadBalancer.Listeners().Delete(ctx, listenerID)
+		logging.Infof("Deleting listener %s", listenerID)
+		err = client.CloudLoadBalancer.Listeners().Delete(ctx, listenerID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Listener %s is not found", listenerID)
-				return
+				return notFoundError("Listener %s is not found", listenerID)
 			}
 		}
+		return nil
 	},
 }
 
@@ -404,20 +451,23 @@ var lbListenerListCmd = &cobra.Command{
 	Long: `List all listeners in a loadbalancer
 Example: bizfly loadbalancer listener list <loadbalancer_id>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		// TODO Check length args
 		lbID := args[0]
 		listeners, err := client.CloudLoadBalancer.Listeners().List(ctx, lbID, &gobizfly.ListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, listener := range listeners {
 			s := []string{listener.ID, listener.Name, listener.Protocol, strconv.Itoa(listener.ProtocolPort), listener.OperatingStatus, listener.DefaultPoolID}
 			data = append(data, s)
 		}
-		formatter.Output(listenerListHeader, data)
+		return formatter.Output(listenerListHeader, data, listeners)
 	},
 }
 
@@ -428,23 +478,25 @@ var lbListenerGetCmd = &cobra.Command{
 	Long: `Get detail a listener with listener  ID as input
 Example: bizfly loadbalancer listener get fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 
 		listener, err := client.CloudLoadBalancer.Listeners().Get(ctx, args[0])
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Listener %s not found.", args[0])
-				return
+				return notFoundError("Listener %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{listener.ID, listener.Name, listener.Protocol, strconv.Itoa(listener.ProtocolPort), listener.OperatingStatus, listener.DefaultPoolID})
-		formatter.Output(listenerListHeader, data)
+		return formatter.Output(listenerListHeader, data, listener)
 	},
 }
 
@@ -455,24 +507,26 @@ var lbHealthMonitorGetCmd = &cobra.Command{
 	Long: `Get health monitor of a listener with listener ID as input
 Example: bizfly loadbalancer listener get fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		healthMontior, err := client.CloudLoadBalancer.HealthMonitors().Get(ctx, args[0])
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Health monitor of listener %s not found.", args[0])
-				return
+				return notFoundError("Health monitor of listener %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{healthMontior.ID, healthMontior.Name, healthMontior.Type,
 			strconv.Itoa(healthMontior.Delay), strconv.Itoa(healthMontior.TimeOut), strconv.Itoa(healthMontior.MaxRetries),
 			healthMontior.DomainName, healthMontior.URLPath})
-		formatter.Output(healthMonitorListHeader, data)
+		return formatter.Output(healthMonitorListHeader, data, healthMontior)
 	},
 }
 
@@ -482,20 +536,23 @@ var lbHealthMonitorDeleteCmd = &cobra.Command{
 	Long: `Delete health monitor of a listener with listener ID as input
 Example: bizfly loadbalancer listener delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.CloudLoadBalancer.HealthMonitors().Delete(ctx, args[0])
+		err = client.CloudLoadBalancer.HealthMonitors().Delete(ctx, args[0])
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Health monitor of listener %s not found.", args[0])
-				return
+				return notFoundError("Health monitor of listener %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
-		fmt.Printf("Health monitor of listener %s deleted.", args[0])
+		logging.Infof("Health monitor of listener %s deleted.", args[0])
+		return nil
 	},
 }
 
@@ -505,11 +562,14 @@ var lbHealthMonitorCreateCmd = &cobra.Command{
 	Long: `Create health monitor of a listener with listener ID as input
 Example: bizfly loadbalancer listener create <pool-id> --name sadjf --type HTTP --delay 10 --timeout 10 --max-retries 3 --domain-name www.google.com --url-path /
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		payload := gobizfly.CloudLoadBalancerHealthMonitorCreateRequest{
 			Name:           healthMonitorName,
 			Type:           healthMonitorProtocol,
@@ -524,13 +584,13 @@ Example: bizfly loadbalancer listener create <pool-id> --name sadjf --type HTTP
 		}
 		healthMonitor, err := client.CloudLoadBalancer.HealthMonitors().Create(ctx, args[0], &payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{healthMonitor.ID, healthMonitor.Name, healthMonitor.Type,
 			strconv.Itoa(healthMonitor.Delay), strconv.Itoa(healthMonitor.TimeOut), strconv.Itoa(healthMonitor.MaxRetries),
 			healthMonitor.DomainName, healthMonitor.URLPath})
-		formatter.Output(healthMonitorListHeader, data)
+		return formatter.Output(healthMonitorListHeader, data, healthMonitor)
 	},
 }
 
@@ -539,11 +599,14 @@ var lbHealthMonitorUpdateCmd = &cobra.Command{
 	Short: "Update health monitor of a listener",
 	Long: `Update health monitor of a listener with listener ID as input
 Example: bizfly loadbalancer listener update <health-monitor-id> --name sadjf --type HTTP --delay 10 --timeout 10 --max-retries 3 --domain-name www.google.com --url-path /`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		payload := gobizfly.CloudLoadBalancerHealthMonitorUpdateRequest{
 			Name:           healthMonitorName,
 			Delay:          &healthMonitorDelay,
@@ -556,13 +619,13 @@ Example: bizfly loadbalancer listener update <health-monitor-id> --name sadjf --
 		}
 		healthMonitor, err := client.CloudLoadBalancer.HealthMonitors().Update(ctx, args[0], &payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{healthMonitor.ID, healthMonitor.Name, healthMonitor.Type,
 			strconv.Itoa(healthMonitor.Delay), strconv.Itoa(healthMonitor.TimeOut), strconv.Itoa(healthMonitor.MaxRetries),
 			healthMonitor.DomainName, healthMonitor.URLPath})
-		formatter.Output(healthMonitorListHeader, data)
+		return formatter.Output(healthMonitorListHeader, data, healthMonitor)
 	},
 }
 
@@ -571,24 +634,33 @@ var lbResizeLoadBalancerCmd = &cobra.Command{
 	Short: "Resize a load balancer",
 	Long: `Resize a load balancer with load balancer ID, new type (small, medium, large, xtralarge)  as input
 	Example: bizfly loadbalancer resize fd554aac-9ab1-11ea-b09d-bbaf82f02f58 medium`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 2 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[2:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[2:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		lbID, err := resolveLoadBalancer(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		lbID := args[0]
 		newType := args[1]
-		err := client.CloudLoadBalancer.Resize(ctx, lbID, newType)
+		err = client.CloudLoadBalancer.Resize(ctx, lbID, newType)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		lb, err := client.CloudLoadBalancer.Get(ctx, lbID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{lb.ID, lb.Name, lb.NetworkType, lb.VipAddress, lb.OperatingStatus, lb.Type})
-		formatter.Output(lbListHeader, data)
+		if err := formatter.Output(lbListHeader, data, lb); err != nil {
+			return err
+		}
+		return waitForUpdate(ctx, "Load balancer "+lbID, loadBalancerTypeUpdate(client, lbID, newType), loadBalancerReadyStatus...)
 	},
 }
 
@@ -597,6 +669,7 @@ func init() {
 	lbCmd.AddCommand(lbListCmd)
 	lbCmd.AddCommand(lbGetCmd)
 	lbCmd.AddCommand(lbDeleteCmd)
+	addWaitFlags(lbResizeLoadBalancerCmd)
 	lbCmd.AddCommand(lbResizeLoadBalancerCmd)
 	lbCmd.AddCommand(lbCreateCmd)
 	lcpf := lbCreateCmd.PersistentFlags()
@@ -620,6 +693,7 @@ func init() {
 	lcpf.StringVar(&healthMonitorURLPath, "health-monitor-url-path", "/", "URL path of the health monitor")
 	lcpf.IntVar(&healthMonitorMaxRetriesDown, "max-retries-down", 3, "Max retries down of the health monitor")
 	lcpf.StringVar(&healthMonitorMethod, "health-monitor-method", "GET", "Method of the health monitor")
+	addWaitFlags(lbCreateCmd)
 
 	lbCmd.AddCommand(lbPoolCmd)
 	lbPoolCmd.AddCommand(lbPoolGetCmd)
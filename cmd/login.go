@@ -17,76 +17,129 @@ limitations under the License.
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
-	"log"
 	"net"
 	"net/http"
-	"os"
+	"net/url"
 	"os/exec"
 	"runtime"
-	"time"
+	"strings"
+	"syscall"
 
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+)
+
+// defaultLoginPort is the port of the local server receiving the login callback
+const defaultLoginPort = 15995
+
+var (
+	loginNoBrowser bool
+	loginPort      int
 )
 
 // loginCmd represents the login command
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to Bizfly Cloud via browser",
-	Long:  `Login to Bizfly Cloud via browser to obtain an authentication token.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Login to Bizfly Cloud via browser to obtain an authentication token.
+Without a browser, e.g. over SSH or in a container, use --no-browser: open the printed URL on
+any machine, log in and paste the URL the browser is redirected to.
+Use: bizfly login [--no-browser] [--port <port>]`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := runLogin(cmd); err != nil {
-			log.Fatalf("Login failed: %v", err)
+			return fmt.Errorf("Login failed: %w", err)
 		}
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(loginCmd)
+	lf := loginCmd.Flags()
+	lf.BoolVar(&loginNoBrowser, "no-browser", false, "Print the login URL and read the ticket from the input instead of opening a browser")
+	lf.IntVar(&loginPort, "port", defaultLoginPort, "Port of the local server receiving the login callback, 0 for a free port")
 }
 
 func runLogin(cmd *cobra.Command) error {
-	// 1. Start a local HTTP server on port 15995
-	listener, err := net.Listen("tcp", "localhost:15995")
+	loginEndpoint, err := authEndpoint()
+	if err != nil {
+		return err
+	}
+	endpoint, err := apiEndpoint()
 	if err != nil {
-		return fmt.Errorf("failed to start local server: %w", err)
+		return err
+	}
+	httpClient, err := newHTTPClient()
+	if err != nil {
+		return err
+	}
+	if loginPort < 0 || loginPort > 65535 {
+		return usageError("Invalid --port %d", loginPort)
+	}
+
+	var token string
+	if loginNoBrowser {
+		token, err = loginWithoutBrowser(cmd, httpClient, loginEndpoint, endpoint)
+	} else {
+		token, err = loginWithBrowser(httpClient, loginEndpoint, endpoint)
+	}
+	if err != nil {
+		return err
+	}
+	return saveLoginToken(cmd, httpClient, endpoint, token)
+}
+
+// loginWithBrowser opens the login page in a browser, which redirects to a
+// local server with the ticket once the user is logged in
+func loginWithBrowser(httpClient *http.Client, loginEndpoint, endpoint string) (string, error) {
+	// 1. Start a local HTTP server, on a free port with --port 0
+	listener, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", loginPort))
+	if err != nil {
+		if errors.Is(err, syscall.EADDRINUSE) {
+			return "", fmt.Errorf("port %d is already in use, choose another one with --port, "+
+				"--port 0 for a free one, or use --no-browser", loginPort)
+		}
+		return "", fmt.Errorf("failed to start local server: %w", err)
 	}
 	defer func() {
-		if cerr := listener.Close(); cerr != nil {
-			log.Printf("failed to close login listener: %v", cerr)
+		if cerr := listener.Close(); cerr != nil && !errors.Is(cerr, net.ErrClosed) {
+			logging.Warnf("failed to close login listener: %v", cerr)
 		}
 	}()
 
-	port := 15995
-	callbackURL := fmt.Sprintf("http://localhost:%d/callback", port)
+	port := listener.Addr().(*net.TCPAddr).Port
+	callbackURL := loginCallbackURL(port)
 
 	// 2. Construct the login URL
-	loginURL := fmt.Sprintf("https://id.bizflycloud.vn/login?service=%s", callbackURL)
+	loginURL := fmt.Sprintf("%s/login?service=%s", loginEndpoint, callbackURL)
 
-	fmt.Printf("Opening browser to login: %s\n", loginURL)
+	logging.Infof("Opening browser to login: %s", loginURL)
 
 	// 3. Open the browser
 	if err := openBrowser(loginURL); err != nil {
-		fmt.Printf("Failed to open browser: %v\n", err)
-		fmt.Printf("Please open the URL manually.\n")
+		logging.Warnf("Failed to open browser: %v", err)
+		logging.Infof("Please open the URL manually, or use --no-browser on a machine without a browser.")
 	}
 
 	// 4. Wait for the callback
-	tokenChan := make(chan string)
-	errChan := make(chan error)
+	tokenChan := make(chan string, 1)
+	errChan := make(chan error, 1)
 
-	server := &http.Server{}
-	http.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+	mux := http.NewServeMux()
+	server := &http.Server{Handler: mux}
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
 		writeResponse := func(format string, args ...interface{}) {
 			if _, err := fmt.Fprintf(w, format, args...); err != nil {
-				log.Printf("failed to write login response: %v", err)
+				logging.Warnf("failed to write login response: %v", err)
 			}
 		}
 
@@ -98,56 +151,12 @@ func runLogin(cmd *cobra.Command) error {
 			return
 		}
 
-		// Call serviceValidate endpoint to get real token
-		validateURL := fmt.Sprintf("https://manage.bizflycloud.vn/cas/serviceValidate?ticket=%s&service=%s", ticket, callbackURL)
-		resp, err := http.Get(validateURL)
-		if err != nil {
-			writeResponse("Login failed: Failed to validate ticket: %v", err)
-			errChan <- fmt.Errorf("failed to validate ticket: %w", err)
-			return
-		}
-		defer func() {
-			if cerr := resp.Body.Close(); cerr != nil {
-				log.Printf("failed to close validation response body: %v", cerr)
-			}
-		}()
-
-		body, err := io.ReadAll(resp.Body)
+		token, err := validateTicket(httpClient, endpoint, ticket, callbackURL)
 		if err != nil {
-			writeResponse("Login failed: Failed to read validation response: %v", err)
-			errChan <- fmt.Errorf("failed to read validation response: %w", err)
+			writeResponse("Login failed: %v", err)
+			errChan <- err
 			return
 		}
-
-		// Parse XML response
-		var casResponse CASServiceResponse
-		if err := xml.Unmarshal(body, &casResponse); err != nil {
-			writeResponse("Login failed: Failed to parse validation response: %v", err)
-			errChan <- fmt.Errorf("failed to parse validation response: %w", err)
-			return
-		}
-
-		// Extract token from response
-		if casResponse.AuthenticationSuccess == nil {
-			writeResponse("Login failed: Authentication failed. Response: %s", string(body))
-			errChan <- fmt.Errorf("authentication failed")
-			return
-		}
-
-		// Get token from attributes section
-		if casResponse.AuthenticationSuccess.Attributes == nil {
-			writeResponse("Login failed: No attributes in validation response. Response: %s", string(body))
-			errChan <- fmt.Errorf("no attributes in validation response")
-			return
-		}
-
-		token := casResponse.AuthenticationSuccess.Attributes.Token
-		if token == "" {
-			writeResponse("Login failed: No token in validation response. Response: %s", string(body))
-			errChan <- fmt.Errorf("no token in validation response")
-			return
-		}
-
 		writeResponse("Login successful! You can close this window.")
 		tokenChan <- token
 	})
@@ -157,147 +166,224 @@ func runLogin(cmd *cobra.Command) error {
 			errChan <- fmt.Errorf("server error: %w", err)
 		}
 	}()
+	defer func() {
+		_ = server.Shutdown(context.Background())
+	}()
 
 	select {
 	case token := <-tokenChan:
-		// 5. Check if project_id is provided
-		// Get project_id from flag, config, or environment variable
-		projID := ""
-		// First try to get from persistent flag (check root command)
-		if rootFlag := cmd.Root().PersistentFlags().Lookup("project-id"); rootFlag != nil {
-			projID = rootFlag.Value.String()
-		}
-		// Fall back to package variable (set by persistent flag)
-		if projID == "" {
-			projID = project_id
+		return token, nil
+	case err := <-errChan:
+		return "", err
+	}
+}
+
+// loginWithoutBrowser prints the login URL and reads the ticket, or the URL
+// the browser was redirected to, from the input. It works over SSH or in a
+// container, where no browser can be opened and the local server can not be
+// reached.
+func loginWithoutBrowser(cmd *cobra.Command, httpClient *http.Client, loginEndpoint, endpoint string) (string, error) {
+	port := loginPort
+	if port == 0 {
+		port = defaultLoginPort
+	}
+	callbackURL := loginCallbackURL(port)
+	loginURL := fmt.Sprintf("%s/login?service=%s", loginEndpoint, callbackURL)
+	out := cmd.ErrOrStderr()
+	fmt.Fprintf(out, "Open this URL in a browser on any machine and log in:\n\n  %s\n\n", loginURL)
+	fmt.Fprintf(out, "The browser is then redirected to %s, which fails to load.\n", callbackURL)
+	fmt.Fprint(out, "Paste the URL of that page, or its ticket: ")
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("failed to read the ticket: %w", err)
+	}
+	ticket, err := parseTicket(line)
+	if err != nil {
+		return "", err
+	}
+	return validateTicket(httpClient, endpoint, ticket, callbackURL)
+}
+
+func loginCallbackURL(port int) string {
+	return fmt.Sprintf("http://localhost:%d/callback", port)
+}
+
+// parseTicket returns the ticket of a pasted callback URL, or the pasted ticket
+func parseTicket(input string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", usageError("No ticket was given")
+	}
+	if !strings.Contains(input, "ticket=") {
+		return input, nil
+	}
+	u, err := url.Parse(input)
+	if err != nil {
+		return "", usageError("Invalid callback URL: %v", err)
+	}
+	query := u.Query()
+	if u.RawQuery == "" {
+		// only the query was pasted
+		query, err = url.ParseQuery(strings.TrimPrefix(input, "?"))
+		if err != nil {
+			return "", usageError("Invalid callback URL: %v", err)
 		}
-		// Finally check config/environment
-		if projID == "" {
-			projID = viper.GetString("project_id")
+	}
+	ticket := query.Get("ticket")
+	if ticket == "" {
+		return "", usageError("No ticket found in %s", input)
+	}
+	return ticket, nil
+}
+
+// validateTicket exchanges a login ticket for a token with the serviceValidate endpoint
+func validateTicket(httpClient *http.Client, endpoint, ticket, callbackURL string) (string, error) {
+	validateURL := fmt.Sprintf("%s/cas/serviceValidate?ticket=%s&service=%s", endpoint,
+		url.QueryEscape(ticket), url.QueryEscape(callbackURL))
+	resp, err := httpClient.Get(validateURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to validate ticket: %w", err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			logging.Warnf("failed to close validation response body: %v", cerr)
 		}
+	}()
 
-		// If project_id is provided, exchange root token for project-scoped token
-		if projID != "" {
-			// Get region for client creation
-			reg := region
-			if reg == "" {
-				reg = viper.GetString("region")
-			}
-			if reg == "" {
-				reg = "HaNoi" // default region
-			}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read validation response: %w", err)
+	}
 
-			regionName := getRegionName(reg)
-			if regionName == "" {
-				return fmt.Errorf("invalid region %s", reg)
-			}
+	// Parse XML response
+	var casResponse CASServiceResponse
+	if err := xml.Unmarshal(body, &casResponse); err != nil {
+		return "", fmt.Errorf("failed to parse validation response: %w", err)
+	}
 
-			// Exchange root token for project-scoped token
-			// Make direct HTTP call since gobizfly library bypasses HTTP request when Token is set
-			ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*10)
-			defer cancelFunc()
+	// Extract token from response
+	if casResponse.AuthenticationSuccess == nil {
+		return "", authError("authentication failed, the ticket may have expired or been used already")
+	}
 
-			// Construct the token endpoint URL based on region
-			// The endpoint format is typically: https://manage.bizflycloud.vn/api/token
-			tokenURL := "https://manage.bizflycloud.vn/api/token"
+	// Get token from attributes section
+	if casResponse.AuthenticationSuccess.Attributes == nil {
+		return "", fmt.Errorf("no attributes in validation response")
+	}
 
-			// Prepare the request payload
-			payload := map[string]string{
-				"auth_method": "token",
-				"token":       token,
-				"project_id":  projID,
-			}
+	token := casResponse.AuthenticationSuccess.Attributes.Token
+	if token == "" {
+		return "", fmt.Errorf("no token in validation response")
+	}
+	return token, nil
+}
 
-			jsonPayload, err := json.Marshal(payload)
-			if err != nil {
-				return fmt.Errorf("failed to marshal request payload: %w", err)
-			}
+// saveLoginToken exchanges the token for a project-scoped one when a project
+// is set, and saves it to the active profile
+func saveLoginToken(cmd *cobra.Command, httpClient *http.Client, endpoint, token string) error {
+	// Get project_id from flag, config, or environment variable
+	projID := ""
+	// First try to get from persistent flag (check root command)
+	if rootFlag := cmd.Root().PersistentFlags().Lookup("project-id"); rootFlag != nil {
+		projID = rootFlag.Value.String()
+	}
+	// Fall back to package variable (set by persistent flag)
+	if projID == "" {
+		projID = project_id
+	}
+	// Finally check config/environment
+	if projID == "" {
+		projID = configValue("project_id")
+	}
 
-			// Create HTTP request
-			req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, bytes.NewBuffer(jsonPayload))
-			if err != nil {
-				return fmt.Errorf("failed to create request: %w", err)
+	// If project_id is provided, exchange root token for project-scoped token
+	if projID != "" {
+		// Get region for client creation
+		reg := region
+		if !cmd.Flags().Changed("region") {
+			if r := configValue("region"); r != "" {
+				reg = r
 			}
+		}
 
-			req.Header.Set("Content-Type", "application/json")
+		regionName := getRegionName(reg)
+		if regionName == "" {
+			return fmt.Errorf("invalid region %s", reg)
+		}
 
-			// Make the HTTP request
-			httpClient := &http.Client{
-				Timeout: time.Second * 10,
-			}
-			resp, err := httpClient.Do(req)
-			if err != nil {
-				return fmt.Errorf("failed to exchange token: %w", err)
-			}
-			defer func() {
-				if cerr := resp.Body.Close(); cerr != nil {
-					log.Printf("failed to close token exchange response body: %v", cerr)
-				}
-			}()
-
-			// Check response status
-			if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-				body, _ := io.ReadAll(resp.Body)
-				return fmt.Errorf("token exchange failed with status %d: %s", resp.StatusCode, string(body))
-			}
+		// Exchange root token for project-scoped token
+		// Make direct HTTP call since gobizfly library bypasses HTTP request when Token is set
+		ctx := context.Background()
 
-			// Parse response
-			var tokenResponse gobizfly.Token
-			if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
-				return fmt.Errorf("failed to decode response: %w", err)
-			}
+		// The token endpoint of the API, https://manage.bizflycloud.vn/api/token by default
+		tokenURL := endpoint + "/api/token"
 
-			// Use the project-scoped token
-			if tokenResponse.KeystoneToken != "" {
-				// Check if the token actually changed
-				if tokenResponse.KeystoneToken == token {
-					fmt.Printf("Warning: Token did not change after exchange. The API returned the same token.\n")
-				}
-				token = tokenResponse.KeystoneToken
-			} else {
-				return fmt.Errorf("received empty token from exchange")
-			}
+		// Prepare the request payload
+		payload := map[string]string{
+			"auth_method": "token",
+			"token":       token,
+			"project_id":  projID,
 		}
-		// Save the token
-		viper.Set("auth_token", token)
-
-		// Get the config file path
-		configPath := viper.ConfigFileUsed()
-		if configPath == "" {
-			// If no config file is set, determine the default path
-			home, err := os.UserHomeDir()
-			if err != nil {
-				return fmt.Errorf("failed to get home dir: %w", err)
-			}
-			configPath = fmt.Sprintf("%s/.bizfly.yaml", home)
-			viper.SetConfigFile(configPath)
+
+		jsonPayload, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request payload: %w", err)
 		}
 
-		// Set config type to yaml to ensure viper knows the format
-		viper.SetConfigType("yaml")
+		// Create HTTP request
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, bytes.NewBuffer(jsonPayload))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
 
-		// Check if config file exists
-		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			// File doesn't exist, use WriteConfigAs to create it with the full path
-			if err := viper.WriteConfigAs(configPath); err != nil {
-				return fmt.Errorf("failed to create config file: %w", err)
-			}
-		} else {
-			// File exists, use WriteConfig to update it
-			if err := viper.WriteConfig(); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
+		req.Header.Set("Content-Type", "application/json")
+
+		// Make the HTTP request
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to exchange token: %w", err)
+		}
+		defer func() {
+			if cerr := resp.Body.Close(); cerr != nil {
+				logging.Warnf("failed to close token exchange response body: %v", cerr)
 			}
+		}()
+
+		// Check response status
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			body, _ := io.ReadAll(resp.Body)
+			return fmt.Errorf("token exchange failed with status %d: %s", resp.StatusCode, string(body))
+		}
+
+		// Parse response
+		var tokenResponse gobizfly.Token
+		if err := json.NewDecoder(resp.Body).Decode(&tokenResponse); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
 		}
 
-		if projID != "" {
-			fmt.Println("Login successful! Project-scoped token saved to config file.")
+		// Use the project-scoped token
+		if tokenResponse.KeystoneToken != "" {
+			// Check if the token actually changed
+			if tokenResponse.KeystoneToken == token {
+				logging.Warnf("Token did not change after exchange. The API returned the same token.")
+			}
+			token = tokenResponse.KeystoneToken
 		} else {
-			fmt.Println("Login successful! Token saved to config file.")
+			return fmt.Errorf("received empty token from exchange")
 		}
-		return server.Shutdown(context.Background())
-	case err := <-errChan:
+	}
+	// Save the token to the active profile
+	store, err := saveProfileSecret("auth_token", token)
+	if err != nil {
 		return err
 	}
+
+	if projID != "" {
+		logging.Infof("Login successful! Project-scoped token saved to %s.", secretStoreLocation(store))
+	} else {
+		logging.Infof("Login successful! Token saved to %s.", secretStoreLocation(store))
+	}
+	return nil
 }
 
 func openBrowser(url string) error {
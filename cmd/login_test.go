@@ -0,0 +1,45 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "testing"
+
+func TestParseTicket(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "ST-12345-abcdef\n", want: "ST-12345-abcdef"},
+		{input: "http://localhost:15995/callback?ticket=ST-12345-abcdef\n", want: "ST-12345-abcdef"},
+		{input: "  http://localhost:8080/callback?ticket=ST-1%2B2&lang=vi  ", want: "ST-1+2"},
+		{input: "?ticket=ST-12345", want: "ST-12345"},
+		{input: "ticket=ST-12345", want: "ST-12345"},
+		{input: "http://localhost:15995/callback?ticket=", wantErr: true},
+		{input: "\n", wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := parseTicket(tc.input)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseTicket(%q) error = %v, want error %v", tc.input, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("parseTicket(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bizflycloud/gobizfly"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const manifestAPIVersion = "bizfly/v1"
+
+// manifestResource is a document of a manifest file
+type manifestResource struct {
+	APIVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   manifestMetadata `yaml:"metadata"`
+	Spec       interface{}      `yaml:"spec"`
+
+	// source is the file and the position of the document, for the messages
+	source string
+	// spec is decoded into the spec type of the kind
+	spec interface{}
+}
+
+type manifestMetadata struct {
+	Name   string            `yaml:"name"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+func (r *manifestResource) String() string {
+	return r.Kind + " " + r.Metadata.Name
+}
+
+// liveResource is the existing resource matching a manifest resource, with
+// the fields of its spec which can be compared
+type liveResource struct {
+	id   string
+	spec interface{}
+}
+
+// fieldChange is a field of a spec which differs from the existing resource
+type fieldChange struct {
+	Field     string      `json:"field" yaml:"field"`
+	From      interface{} `json:"from" yaml:"from"`
+	To        interface{} `json:"to" yaml:"to"`
+	Updatable bool        `json:"updatable" yaml:"updatable"`
+}
+
+// manifestKind describes how the resources of a kind are found, compared,
+// created and updated
+type manifestKind struct {
+	newSpec func() interface{}
+	// normalize resolves the references of a spec to IDs and fills the defaults
+	normalize func(ctx context.Context, client *gobizfly.Client, res *manifestResource) error
+	// find returns the existing resource, or nil when it does not exist
+	find   func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (*liveResource, error)
+	diff   func(desired, live interface{}) []fieldChange
+	create func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (string, error)
+	// update applies the updatable changes
+	update func(ctx context.Context, client *gobizfly.Client, res *manifestResource, live *liveResource, changes []fieldChange) error
+}
+
+// Actions of a manifest plan
+const (
+	actionCreate    = "create"
+	actionUpdate    = "update"
+	actionUnchanged = "unchanged"
+)
+
+// manifestPlan is what apply does for a resource
+type manifestPlan struct {
+	resource *manifestResource
+	action   string
+	live     *liveResource
+	changes  []fieldChange
+}
+
+// updatable returns the changes which can be applied in place
+func (p *manifestPlan) updatable() []fieldChange {
+	var changes []fieldChange
+	for _, change := range p.changes {
+		if change.Updatable {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// readManifests reads the resources of manifest files, "-" is the standard input
+func readManifests(paths []string) ([]*manifestResource, error) {
+	if len(paths) == 0 {
+		return nil, usageError("You need to specify a manifest file with --filename")
+	}
+	var resources []*manifestResource
+	var err error
+	for _, path := range paths {
+		var decoded []*manifestResource
+		if path == "-" {
+			decoded, err = decodeManifest("stdin", os.Stdin)
+		} else {
+			f, openErr := os.Open(path)
+			if openErr != nil {
+				return nil, usageError("Read manifest error: %v", openErr)
+			}
+			decoded, err = decodeManifest(path, f)
+			_ = f.Close()
+		}
+		if err != nil {
+			return nil, err
+		}
+		resources = append(resources, decoded...)
+	}
+	seen := make(map[string]string)
+	for _, res := range resources {
+		key := res.Kind + "/" + res.Metadata.Name
+		if spec, ok := res.spec.(*dnsRecordManifestSpec); ok {
+			// records of several zones and types share their names
+			key += "/" + spec.Zone + "/" + spec.Type
+		}
+		if source, ok := seen[key]; ok {
+			return nil, usageError("%s: %s is already defined in %s", res.source, res, source)
+		}
+		seen[key] = res.source
+	}
+	return resources, nil
+}
+
+// decodeManifest decodes the documents of a multi-document YAML stream
+func decodeManifest(path string, r io.Reader) ([]*manifestResource, error) {
+	var resources []*manifestResource
+	decoder := yaml.NewDecoder(r)
+	for i := 1; ; i++ {
+		var res manifestResource
+		err := decoder.Decode(&res)
+		if errors.Is(err, io.EOF) {
+			return resources, nil
+		}
+		source := fmt.Sprintf("%s, document %d", path, i)
+		if err != nil {
+			return nil, usageError("%s: %v", source, err)
+		}
+		if res.Kind == "" && res.Spec == nil && res.Metadata.Name == "" {
+			// empty document, e.g. after a trailing "---"
+			continue
+		}
+		res.source = source
+		if err := res.validate(); err != nil {
+			return nil, err
+		}
+		resources = append(resources, &res)
+	}
+}
+
+// validate checks the document and decodes its spec
+func (r *manifestResource) validate() error {
+	if r.APIVersion != "" && r.APIVersion != manifestAPIVersion {
+		return usageError("%s: unsupported apiVersion %q, use %s", r.source, r.APIVersion, manifestAPIVersion)
+	}
+	kind, ok := manifestKinds[r.Kind]
+	if !ok {
+		return usageError("%s: unknown kind %q, use one of: %s", r.source, r.Kind, strings.Join(manifestKindNames(), ", "))
+	}
+	if r.Metadata.Name == "" {
+		return usageError("%s: metadata.name is required", r.source)
+	}
+	b, err := yaml.Marshal(r.Spec)
+	if err != nil {
+		return usageError("%s: %v", r.source, err)
+	}
+	r.spec = kind.newSpec()
+	if r.Spec != nil {
+		if err := yaml.UnmarshalStrict(b, r.spec); err != nil {
+			return usageError("%s: invalid spec of %s: %v", r.source, r, err)
+		}
+	}
+	return nil
+}
+
+func manifestKindNames() []string {
+	names := make([]string, 0, len(manifestKinds))
+	for name := range manifestKinds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// planManifest compares a resource with the existing one
+func planManifest(ctx context.Context, client *gobizfly.Client, res *manifestResource) (*manifestPlan, error) {
+	kind := manifestKinds[res.Kind]
+	if kind.normalize != nil {
+		if err := kind.normalize(ctx, client, res); err != nil {
+			return nil, fmt.Errorf("%s: %w", res.source, err)
+		}
+	}
+	live, err := kind.find(ctx, client, res)
+	if err != nil {
+		return nil, fmt.Errorf("%s: find %s: %w", res.source, res, err)
+	}
+	if live == nil {
+		return &manifestPlan{resource: res, action: actionCreate}, nil
+	}
+	plan := &manifestPlan{resource: res, action: actionUnchanged, live: live, changes: kind.diff(res.spec, live.spec)}
+	if len(plan.changes) > 0 {
+		plan.action = actionUpdate
+	}
+	return plan, nil
+}
+
+// manifestCandidate is an existing resource which may match a manifest resource
+type manifestCandidate struct {
+	id   string
+	name string
+	tags []string
+}
+
+// matchManifest returns the ID of the candidate with the name of the
+// resource or, when there is none, the one tagged with all its labels as
+// key=value. It returns an empty ID when nothing matches.
+func matchManifest(res *manifestResource, candidates []manifestCandidate) (string, error) {
+	var byName, byLabels []resourceRef
+	for _, c := range candidates {
+		if c.name == res.Metadata.Name {
+			byName = append(byName, resourceRef{id: c.id, names: []string{c.name}})
+		} else if len(res.Metadata.Labels) > 0 && hasLabels(c.tags, res.Metadata.Labels) {
+			byLabels = append(byLabels, resourceRef{id: c.id, names: []string{c.name}})
+		}
+	}
+	for _, matches := range [][]resourceRef{byName, byLabels} {
+		switch {
+		case len(matches) == 1:
+			return matches[0].id, nil
+		case len(matches) > 1:
+			return "", ambiguousError(strings.ToLower(res.Kind), res.Metadata.Name, matches)
+		}
+	}
+	return "", nil
+}
+
+func hasLabels(tags []string, labels map[string]string) bool {
+	for key, value := range labels {
+		found := false
+		for _, tag := range tags {
+			if tag == key+"="+value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// compareFields compares the fields of two specs by their YAML names. The
+// fields which are not set in the desired spec are not compared.
+func compareFields(desired, live interface{}, updatable []string, fields ...string) []fieldChange {
+	desiredFields := specFields(desired)
+	liveFields := specFields(live)
+	var changes []fieldChange
+	for _, field := range fields {
+		to, ok := desiredFields[field]
+		if !ok {
+			continue
+		}
+		from := liveFields[field]
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+		change := fieldChange{Field: field, From: from, To: to}
+		for _, f := range updatable {
+			if f == field {
+				change.Updatable = true
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// specFields returns the fields of a spec which are set, by their YAML names
+func specFields(spec interface{}) map[string]interface{} {
+	fields := make(map[string]interface{})
+	b, err := yaml.Marshal(spec)
+	if err != nil {
+		return fields
+	}
+	_ = yaml.Unmarshal(b, &fields)
+	return fields
+}
+
+// hasChange reports whether a field is in the changes
+func hasChange(changes []fieldChange, field string) bool {
+	for _, change := range changes {
+		if change.Field == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,864 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bizflycloud/gobizfly"
+)
+
+// manifestKinds are the kinds of resources of a manifest
+var manifestKinds = map[string]manifestKind{
+	"Server":            serverManifestKind,
+	"Volume":            volumeManifestKind,
+	"VPC":               vpcManifestKind,
+	"Firewall":          firewallManifestKind,
+	"LoadBalancer":      loadBalancerManifestKind,
+	"DNSRecord":         dnsRecordManifestKind,
+	"KubernetesCluster": clusterManifestKind,
+}
+
+// resolveReference resolves a reference to another resource of a spec. A
+// resource which does not exist yet is kept by name, it is resolved again
+// when the resource is created or updated.
+func resolveReference(ctx context.Context, client *gobizfly.Client, value string,
+	resolver func(ctx context.Context, client *gobizfly.Client, value string) (string, error)) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	id, err := resolver(ctx, client, value)
+	if isNotFound(err) {
+		return value, nil
+	}
+	return id, err
+}
+
+type serverManifestSpec struct {
+	Flavor            string              `yaml:"flavor"`
+	Category          string              `yaml:"category,omitempty"`
+	AvailabilityZone  string              `yaml:"availabilityZone,omitempty"`
+	Image             string              `yaml:"image,omitempty"`
+	Volume            string              `yaml:"volume,omitempty"`
+	Snapshot          string              `yaml:"snapshot,omitempty"`
+	RootDisk          *serverManifestDisk `yaml:"rootDisk,omitempty"`
+	SSHKey            string              `yaml:"sshKey,omitempty"`
+	Firewalls         []string            `yaml:"firewalls,omitempty"`
+	NetworkInterfaces []string            `yaml:"networkInterfaces,omitempty"`
+	NetworkPlan       string              `yaml:"networkPlan,omitempty"`
+	BillingPlan       string              `yaml:"billingPlan,omitempty"`
+	WanIP             *bool               `yaml:"wanIP,omitempty"`
+	UserData          string              `yaml:"userData,omitempty"`
+}
+
+type serverManifestDisk struct {
+	Size       int    `yaml:"size"`
+	Type       string `yaml:"type,omitempty"`
+	VolumeType string `yaml:"volumeType,omitempty"`
+}
+
+var serverManifestKind = manifestKind{
+	newSpec: func() interface{} { return &serverManifestSpec{} },
+	normalize: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) error {
+		spec := res.spec.(*serverManifestSpec)
+		if spec.Flavor == "" {
+			return usageError("spec.flavor of %s is required", res)
+		}
+		if err := validateAvailabilityZone(spec.AvailabilityZone, getRegionName(region)); err != nil {
+			return usageError("%s: %v", res, err)
+		}
+		sources := 0
+		for _, source := range []string{spec.Image, spec.Volume, spec.Snapshot} {
+			if source != "" {
+				sources++
+			}
+		}
+		if sources != 1 {
+			return usageError("%s needs exactly one of spec.image, spec.volume and spec.snapshot", res)
+		}
+		if err := validateUserData(spec.UserData); err != nil {
+			return usageError("%s: %v", res, err)
+		}
+		var err error
+		spec.SSHKey, err = resolveReference(ctx, client, spec.SSHKey, resolveSSHKey)
+		return err
+	},
+	find: findServerManifest,
+	diff: func(desired, live interface{}) []fieldChange {
+		return compareFields(desired, live, []string{"flavor"}, "flavor", "category", "availabilityZone", "sshKey")
+	},
+	create: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (string, error) {
+		spec := res.spec.(*serverManifestSpec)
+		// the defaults are not set in normalize so that diff does not compare
+		// them, serverCreateRequest defaults the category to premium
+		if spec.AvailabilityZone == "" {
+			spec.AvailabilityZone = defaultAvailabilityZone(getRegionName(region))
+		}
+		scr, err := serverCreateRequest(ctx, client, res.Metadata.Name, spec)
+		if err != nil {
+			return "", err
+		}
+		task, err := client.CloudServer.Create(ctx, scr)
+		if err != nil {
+			return "", err
+		}
+		for _, taskID := range task.Task {
+			if err := waitForTask(ctx, client, taskID); err != nil {
+				return "", err
+			}
+		}
+		// create only returns the tasks, the server is looked up once it is
+		// listed, even without --wait
+		var id string
+		if dryRun {
+			return id, nil
+		}
+		err = poll(ctx, res.String(), func(ctx context.Context) (string, bool, error) {
+			live, err := findServerManifest(ctx, client, res)
+			if err != nil || live == nil {
+				return "not listed yet", false, err
+			}
+			id = live.id
+			return "listed", true, nil
+		})
+		return id, err
+	},
+	update: func(ctx context.Context, client *gobizfly.Client, res *manifestResource, live *liveResource, changes []fieldChange) error {
+		spec := res.spec.(*serverManifestSpec)
+		if hasChange(changes, "flavor") {
+			task, err := client.CloudServer.Resize(ctx, live.id, spec.Flavor)
+			if err != nil {
+				return fmt.Errorf("Resize server error: %w", err)
+			}
+			return waitForTask(ctx, client, task.TaskID)
+		}
+		return nil
+	},
+}
+
+// findServerManifest finds the server of a resource by name. The labels
+// cannot be written to a server, so they are not matched.
+func findServerManifest(ctx context.Context, client *gobizfly.Client, res *manifestResource) (*liveResource, error) {
+	servers, err := client.CloudServer.List(ctx, &gobizfly.ServerListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]manifestCandidate, 0, len(servers))
+	for _, server := range servers {
+		candidates = append(candidates, manifestCandidate{id: server.ID, name: server.Name})
+	}
+	id, err := matchManifest(res, candidates)
+	if err != nil || id == "" {
+		return nil, err
+	}
+	for _, server := range servers {
+		if server.ID == id {
+			return &liveResource{id: id, spec: &serverManifestSpec{
+				Flavor:           server.FlavorName,
+				Category:         server.Category,
+				AvailabilityZone: server.AvailabilityZone,
+				SSHKey:           server.KeyName,
+			}}, nil
+		}
+	}
+	return nil, nil
+}
+
+type volumeManifestSpec struct {
+	Size             int    `yaml:"size"`
+	Type             string `yaml:"type,omitempty"`
+	Category         string `yaml:"category,omitempty"`
+	AvailabilityZone string `yaml:"availabilityZone,omitempty"`
+	Snapshot         string `yaml:"snapshot,omitempty"`
+	Server           string `yaml:"server,omitempty"`
+	Description      string `yaml:"description,omitempty"`
+}
+
+var volumeManifestKind = manifestKind{
+	newSpec: func() interface{} { return &volumeManifestSpec{} },
+	normalize: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) error {
+		spec := res.spec.(*volumeManifestSpec)
+		if spec.Size <= 0 {
+			return usageError("spec.size of %s is required", res)
+		}
+		var err error
+		spec.Server, err = resolveReference(ctx, client, spec.Server, resolveServer)
+		return err
+	},
+	find: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (*liveResource, error) {
+		volumes, err := client.CloudServer.Volumes().List(ctx, &gobizfly.VolumeListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]manifestCandidate, 0, len(volumes))
+		for _, volume := range volumes {
+			candidates = append(candidates, manifestCandidate{id: volume.ID, name: volume.Name})
+		}
+		id, err := matchManifest(res, candidates)
+		if err != nil || id == "" {
+			return nil, err
+		}
+		for _, volume := range volumes {
+			if volume.ID == id {
+				spec := &volumeManifestSpec{
+					Size:             volume.Size,
+					Type:             volume.VolumeType,
+					AvailabilityZone: volume.AvailabilityZone,
+					Description:      volume.Description,
+				}
+				if len(volume.Attachments) > 0 {
+					spec.Server = volume.Attachments[0].ServerID
+				}
+				return &liveResource{id: id, spec: spec}, nil
+			}
+		}
+		return nil, nil
+	},
+	diff: func(desired, live interface{}) []fieldChange {
+		return compareFields(desired, live, []string{"size", "description", "server"},
+			"size", "type", "availabilityZone", "description", "server")
+	},
+	create: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (string, error) {
+		spec := res.spec.(*volumeManifestSpec)
+		vcr := gobizfly.VolumeCreateRequest{
+			Name:             res.Metadata.Name,
+			Size:             spec.Size,
+			VolumeType:       spec.Type,
+			SnapshotID:       spec.Snapshot,
+			AvailabilityZone: spec.AvailabilityZone,
+			VolumeCategory:   spec.Category,
+			Description:      spec.Description,
+		}
+		if spec.Server != "" {
+			var err error
+			if vcr.ServerID, err = resolveServer(ctx, client, spec.Server); err != nil {
+				return "", err
+			}
+		}
+		volume, err := client.CloudServer.Volumes().Create(ctx, &vcr)
+		if err != nil {
+			return "", err
+		}
+		return volume.ID, waitForStatus(ctx, res.String(), volumeStatus(client, volume.ID), volumeReadyStatus...)
+	},
+	update: func(ctx context.Context, client *gobizfly.Client, res *manifestResource, live *liveResource, changes []fieldChange) error {
+		spec := res.spec.(*volumeManifestSpec)
+		current := live.spec.(*volumeManifestSpec)
+		if hasChange(changes, "size") {
+			if spec.Size < current.Size {
+				return usageError("%s can not be shrunk from %d to %d GB", res, current.Size, spec.Size)
+			}
+			if _, err := client.CloudServer.Volumes().ExtendVolume(ctx, live.id, spec.Size); err != nil {
+				return fmt.Errorf("Extend volume error: %w", err)
+			}
+			if err := waitForUpdate(ctx, res.String(), volumeSizeUpdate(client, live.id, spec.Size), volumeReadyStatus...); err != nil {
+				return err
+			}
+		}
+		if hasChange(changes, "description") {
+			if _, err := client.CloudServer.Volumes().Patch(ctx, live.id, &gobizfly.VolumePatchRequest{Description: spec.Description}); err != nil {
+				return fmt.Errorf("Update volume error: %w", err)
+			}
+		}
+		if hasChange(changes, "server") {
+			serverID, err := resolveServer(ctx, client, spec.Server)
+			if err != nil {
+				return err
+			}
+			if current.Server != "" {
+				if _, err := client.CloudServer.Volumes().Detach(ctx, live.id, current.Server); err != nil {
+					return fmt.Errorf("Detach a volume from a server error: %w", err)
+				}
+				if err := waitForStatus(ctx, res.String(), volumeStatus(client, live.id), "available"); err != nil {
+					return err
+				}
+			}
+			if _, err := client.CloudServer.Volumes().Attach(ctx, live.id, serverID); err != nil {
+				return fmt.Errorf("Attach a volume to a server error: %w", err)
+			}
+			return waitForStatus(ctx, res.String(), volumeStatus(client, live.id), volumeAttachedStatus...)
+		}
+		return nil
+	},
+}
+
+type vpcManifestSpec struct {
+	CIDR        string `yaml:"cidr,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Default     *bool  `yaml:"default,omitempty"`
+}
+
+var vpcManifestKind = manifestKind{
+	newSpec: func() interface{} { return &vpcManifestSpec{} },
+	find: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (*liveResource, error) {
+		vpcs, err := client.CloudServer.VPCNetworks().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]manifestCandidate, 0, len(vpcs))
+		for _, vpc := range vpcs {
+			candidates = append(candidates, manifestCandidate{id: vpc.ID, name: vpc.Name, tags: vpc.Tags})
+		}
+		id, err := matchManifest(res, candidates)
+		if err != nil || id == "" {
+			return nil, err
+		}
+		for _, vpc := range vpcs {
+			if vpc.ID == id {
+				isDefault := vpc.IsDefault
+				spec := &vpcManifestSpec{Description: vpc.Description, Default: &isDefault}
+				if len(vpc.Subnets) > 0 {
+					spec.CIDR = vpc.Subnets[0].CIDR
+				}
+				return &liveResource{id: id, spec: spec}, nil
+			}
+		}
+		return nil, nil
+	},
+	diff: func(desired, live interface{}) []fieldChange {
+		fields := []string{"cidr", "description", "default"}
+		return compareFields(desired, live, fields, fields...)
+	},
+	create: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (string, error) {
+		spec := res.spec.(*vpcManifestSpec)
+		vpc, err := client.CloudServer.VPCNetworks().Create(ctx, &gobizfly.CreateVPCPayload{
+			Name:        res.Metadata.Name,
+			Description: spec.Description,
+			CIDR:        spec.CIDR,
+			IsDefault:   spec.Default != nil && *spec.Default,
+		})
+		if err != nil {
+			return "", err
+		}
+		return vpc.ID, nil
+	},
+	update: func(ctx context.Context, client *gobizfly.Client, res *manifestResource, live *liveResource, changes []fieldChange) error {
+		spec := res.spec.(*vpcManifestSpec)
+		current := live.spec.(*vpcManifestSpec)
+		payload := gobizfly.UpdateVPCPayload{
+			Name:        res.Metadata.Name,
+			Description: current.Description,
+			CIDR:        current.CIDR,
+			IsDefault:   *current.Default,
+		}
+		if hasChange(changes, "description") {
+			payload.Description = spec.Description
+		}
+		if hasChange(changes, "cidr") {
+			payload.CIDR = spec.CIDR
+		}
+		if hasChange(changes, "default") {
+			payload.IsDefault = *spec.Default
+		}
+		if _, err := client.CloudServer.VPCNetworks().Update(ctx, live.id, &payload); err != nil {
+			return fmt.Errorf("Update VPC error: %w", err)
+		}
+		return nil
+	},
+}
+
+type firewallManifestSpec struct {
+	Inbound  []firewallManifestRule `yaml:"inbound,omitempty"`
+	Outbound []firewallManifestRule `yaml:"outbound,omitempty"`
+}
+
+type firewallManifestRule struct {
+	Type      string `yaml:"type,omitempty"`
+	Protocol  string `yaml:"protocol"`
+	PortRange string `yaml:"portRange,omitempty"`
+	CIDR      string `yaml:"cidr"`
+}
+
+// sortFirewallRules orders the rules so that their order in the manifest does not matter
+func sortFirewallRules(rules []firewallManifestRule) {
+	sort.Slice(rules, func(i, j int) bool {
+		return fmt.Sprint(rules[i]) < fmt.Sprint(rules[j])
+	})
+}
+
+func firewallRules(rules []firewallManifestRule) []gobizfly.FirewallRuleCreateRequest {
+	requests := make([]gobizfly.FirewallRuleCreateRequest, 0, len(rules))
+	for _, rule := range rules {
+		requests = append(requests, gobizfly.FirewallRuleCreateRequest{
+			Type:      rule.Type,
+			Protocol:  rule.Protocol,
+			PortRange: rule.PortRange,
+			CIDR:      rule.CIDR,
+		})
+	}
+	return requests
+}
+
+var firewallManifestKind = manifestKind{
+	newSpec: func() interface{} { return &firewallManifestSpec{} },
+	normalize: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) error {
+		spec := res.spec.(*firewallManifestSpec)
+		for _, rules := range [][]firewallManifestRule{spec.Inbound, spec.Outbound} {
+			for i := range rules {
+				if rules[i].Type == "" {
+					rules[i].Type = "CUSTOM"
+				}
+			}
+			sortFirewallRules(rules)
+		}
+		return nil
+	},
+	find: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (*liveResource, error) {
+		firewalls, err := client.CloudServer.Firewalls().List(ctx, &gobizfly.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]manifestCandidate, 0, len(firewalls))
+		for _, firewall := range firewalls {
+			candidates = append(candidates, manifestCandidate{id: firewall.ID, name: firewall.Name})
+		}
+		id, err := matchManifest(res, candidates)
+		if err != nil || id == "" {
+			return nil, err
+		}
+		firewall, err := client.CloudServer.Firewalls().Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		spec := &firewallManifestSpec{}
+		for _, rule := range firewall.InBound {
+			spec.Inbound = append(spec.Inbound, firewallManifestRule{Type: rule.Type, Protocol: rule.Protocol, PortRange: rule.PortRange, CIDR: rule.CIDR})
+		}
+		for _, rule := range firewall.OutBound {
+			spec.Outbound = append(spec.Outbound, firewallManifestRule{Type: rule.Type, Protocol: rule.Protocol, PortRange: rule.PortRange, CIDR: rule.CIDR})
+		}
+		sortFirewallRules(spec.Inbound)
+		sortFirewallRules(spec.Outbound)
+		return &liveResource{id: id, spec: spec}, nil
+	},
+	diff: func(desired, live interface{}) []fieldChange {
+		fields := []string{"inbound", "outbound"}
+		return compareFields(desired, live, fields, fields...)
+	},
+	create: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (string, error) {
+		spec := res.spec.(*firewallManifestSpec)
+		firewall, err := client.CloudServer.Firewalls().Create(ctx, &gobizfly.FirewallRequestPayload{
+			Name:     res.Metadata.Name,
+			InBound:  firewallRules(spec.Inbound),
+			OutBound: firewallRules(spec.Outbound),
+		})
+		if err != nil {
+			return "", err
+		}
+		return firewall.ID, nil
+	},
+	update: func(ctx context.Context, client *gobizfly.Client, res *manifestResource, live *liveResource, changes []fieldChange) error {
+		spec := res.spec.(*firewallManifestSpec)
+		current := live.spec.(*firewallManifestSpec)
+		payload := gobizfly.FirewallRequestPayload{
+			Name:     res.Metadata.Name,
+			InBound:  firewallRules(current.Inbound),
+			OutBound: firewallRules(current.Outbound),
+		}
+		if hasChange(changes, "inbound") {
+			payload.InBound = firewallRules(spec.Inbound)
+		}
+		if hasChange(changes, "outbound") {
+			payload.OutBound = firewallRules(spec.Outbound)
+		}
+		if _, err := client.CloudServer.Firewalls().Update(ctx, live.id, &payload); err != nil {
+			return fmt.Errorf("Update firewall error: %w", err)
+		}
+		return nil
+	},
+}
+
+type loadBalancerManifestSpec struct {
+	Type        string                         `yaml:"type"`
+	NetworkType string                         `yaml:"networkType,omitempty"`
+	Network     string                         `yaml:"network,omitempty"`
+	Description string                         `yaml:"description,omitempty"`
+	Listeners   []loadBalancerManifestListener `yaml:"listeners,omitempty"`
+}
+
+type loadBalancerManifestListener struct {
+	Name      string `yaml:"name,omitempty"`
+	Protocol  string `yaml:"protocol,omitempty"`
+	Port      int    `yaml:"port,omitempty"`
+	Algorithm string `yaml:"algorithm,omitempty"`
+}
+
+var loadBalancerManifestKind = manifestKind{
+	newSpec: func() interface{} { return &loadBalancerManifestSpec{} },
+	normalize: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) error {
+		spec := res.spec.(*loadBalancerManifestSpec)
+		if spec.Type == "" {
+			return usageError("spec.type of %s is required", res)
+		}
+		return nil
+	},
+	find: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (*liveResource, error) {
+		lbs, err := client.CloudLoadBalancer.List(ctx, &gobizfly.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]manifestCandidate, 0, len(lbs))
+		for _, lb := range lbs {
+			candidates = append(candidates, manifestCandidate{id: lb.ID, name: lb.Name})
+		}
+		id, err := matchManifest(res, candidates)
+		if err != nil || id == "" {
+			return nil, err
+		}
+		for _, lb := range lbs {
+			if lb.ID == id {
+				return &liveResource{id: id, spec: &loadBalancerManifestSpec{Type: lb.Type, NetworkType: lb.NetworkType}}, nil
+			}
+		}
+		return nil, nil
+	},
+	diff: func(desired, live interface{}) []fieldChange {
+		return compareFields(desired, live, []string{"type"}, "type", "networkType")
+	},
+	create: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (string, error) {
+		spec := res.spec.(*loadBalancerManifestSpec)
+		payload := gobizfly.LoadBalancerCreateRequest{
+			Name:        res.Metadata.Name,
+			Type:        spec.Type,
+			NetworkType: spec.NetworkType,
+			Description: spec.Description,
+		}
+		// the default is not set in normalize so that diff does not compare it
+		if payload.NetworkType == "" {
+			payload.NetworkType = "external"
+		}
+		if spec.Network != "" {
+			var err error
+			if payload.VPCNetworkID, err = resolveVPC(ctx, client, spec.Network); err != nil {
+				return "", err
+			}
+		}
+		listeners := spec.Listeners
+		if len(listeners) == 0 {
+			listeners = []loadBalancerManifestListener{{}}
+		}
+		for _, listener := range listeners {
+			// the defaults are the ones of loadbalancer create
+			if listener.Name == "" {
+				listener.Name = "Default Listener"
+			}
+			if listener.Protocol == "" {
+				listener.Protocol = "HTTP"
+			}
+			if listener.Port == 0 {
+				listener.Port = 80
+			}
+			if listener.Algorithm == "" {
+				listener.Algorithm = "ROUND_ROBIN"
+			}
+			payload.Listeners = append(payload.Listeners, gobizfly.LoadBalancerListener{
+				Name:         listener.Name,
+				Protocol:     listener.Protocol,
+				ProtocolPort: listener.Port,
+				DefaultPool: gobizfly.ListenerPool{
+					LbAlgorithm: listener.Algorithm,
+					Name:        listener.Name + " Pool",
+					Protocol:    listener.Protocol,
+					Members:     []string{},
+					CloudLoadBalancerHealthMonitor: gobizfly.ListenerHealthMonitor{
+						Delay:          5,
+						MaxRetries:     3,
+						Timeout:        5,
+						ExpectedCodes:  "200",
+						URLPath:        "/",
+						MaxRetriesDown: 3,
+						Type:           "HTTP",
+						HTTPMethod:     "GET",
+					},
+				},
+			})
+		}
+		lb, err := client.CloudLoadBalancer.Create(ctx, &payload)
+		if err != nil {
+			return "", err
+		}
+		return lb.ID, waitForStatus(ctx, res.String(), loadBalancerStatus(client, lb.ID), loadBalancerReadyStatus...)
+	},
+	update: func(ctx context.Context, client *gobizfly.Client, res *manifestResource, live *liveResource, changes []fieldChange) error {
+		spec := res.spec.(*loadBalancerManifestSpec)
+		if hasChange(changes, "type") {
+			if err := client.CloudLoadBalancer.Resize(ctx, live.id, spec.Type); err != nil {
+				return fmt.Errorf("Resize load balancer error: %w", err)
+			}
+			return waitForUpdate(ctx, res.String(), loadBalancerTypeUpdate(client, live.id, spec.Type), loadBalancerReadyStatus...)
+		}
+		return nil
+	},
+}
+
+// dnsRecordManifestSpec is a record of a zone, the name of the resource is
+// the name of the record. The data of MX records is given as <domain>:<priority>.
+type dnsRecordManifestSpec struct {
+	Zone string   `yaml:"zone"`
+	Type string   `yaml:"type"`
+	TTL  int      `yaml:"ttl,omitempty"`
+	Data []string `yaml:"data,omitempty"`
+}
+
+// dnsRecordPayload returns the payload creating or updating a record
+func dnsRecordPayload(name string, spec *dnsRecordManifestSpec) (recordPayload, error) {
+	base := gobizfly.BaseCreateRecordPayload{Name: name, Type: spec.Type, TTL: spec.TTL}
+	if spec.Type == "MX" {
+		mxData, err := parseMXRecord(spec.Data)
+		if err != nil {
+			return recordPayload{}, err
+		}
+		return recordPayload{Record: gobizfly.CreateMXRecordPayload{BaseCreateRecordPayload: base, Data: mxData}}, nil
+	}
+	return recordPayload{Record: gobizfly.CreateNormalRecordPayload{BaseCreateRecordPayload: base, Data: spec.Data}}, nil
+}
+
+// dnsZoneID returns the ID of a zone given by name or ID
+func dnsZoneID(ctx context.Context, client *gobizfly.Client, zone string) (string, error) {
+	zones, err := client.DNS.ListZones(ctx, &gobizfly.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for _, z := range zones.Zones {
+		if z.ID == zone || z.Name == zone || strings.TrimSuffix(z.Name, ".") == strings.TrimSuffix(zone, ".") {
+			return z.ID, nil
+		}
+	}
+	return "", notFoundError("DNS zone %s is not found", zone)
+}
+
+var dnsRecordManifestKind = manifestKind{
+	newSpec: func() interface{} { return &dnsRecordManifestSpec{} },
+	normalize: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) error {
+		spec := res.spec.(*dnsRecordManifestSpec)
+		if spec.Zone == "" {
+			return usageError("spec.zone of %s is required", res)
+		}
+		spec.Type = strings.ToUpper(spec.Type)
+		if !checkValidType(spec.Type, NormalTypes) && spec.Type != "MX" {
+			return usageError("unsupported record type %q of %s", spec.Type, res)
+		}
+		sort.Strings(spec.Data)
+		return nil
+	},
+	find: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (*liveResource, error) {
+		spec := res.spec.(*dnsRecordManifestSpec)
+		zoneID, err := dnsZoneID(ctx, client, spec.Zone)
+		if err != nil {
+			return nil, err
+		}
+		zone, err := client.DNS.GetZone(ctx, zoneID)
+		if err != nil {
+			return nil, err
+		}
+		for _, recordSet := range zone.RecordsSet {
+			if recordSet.Name != res.Metadata.Name || recordSet.Type != spec.Type {
+				continue
+			}
+			record, err := client.DNS.GetRecord(ctx, recordSet.ID)
+			if err != nil {
+				return nil, err
+			}
+			live := &dnsRecordManifestSpec{Zone: spec.Zone, Type: record.Type, TTL: record.TTL}
+			for _, data := range record.Data {
+				if domainMap, ok := data.(map[string]interface{}); ok {
+					live.Data = append(live.Data, fmt.Sprintf("%v:%v", domainMap["value"], domainMap["priority"]))
+				} else {
+					live.Data = append(live.Data, fmt.Sprintf("%v", data))
+				}
+			}
+			sort.Strings(live.Data)
+			return &liveResource{id: recordSet.ID, spec: live}, nil
+		}
+		return nil, nil
+	},
+	diff: func(desired, live interface{}) []fieldChange {
+		fields := []string{"ttl", "data"}
+		return compareFields(desired, live, fields, fields...)
+	},
+	create: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (string, error) {
+		spec := res.spec.(*dnsRecordManifestSpec)
+		zoneID, err := dnsZoneID(ctx, client, spec.Zone)
+		if err != nil {
+			return "", err
+		}
+		payload, err := dnsRecordPayload(res.Metadata.Name, spec)
+		if err != nil {
+			return "", err
+		}
+		record, err := client.DNS.CreateRecord(ctx, zoneID, payload)
+		if err != nil {
+			return "", err
+		}
+		return record.ID, nil
+	},
+	update: func(ctx context.Context, client *gobizfly.Client, res *manifestResource, live *liveResource, changes []fieldChange) error {
+		spec := *res.spec.(*dnsRecordManifestSpec)
+		current := live.spec.(*dnsRecordManifestSpec)
+		if !hasChange(changes, "ttl") {
+			spec.TTL = current.TTL
+		}
+		if !hasChange(changes, "data") {
+			spec.Data = current.Data
+		}
+		payload, err := dnsRecordPayload(res.Metadata.Name, &spec)
+		if err != nil {
+			return err
+		}
+		if _, err := client.DNS.UpdateRecord(ctx, live.id, payload); err != nil {
+			return fmt.Errorf("Update record error: %w", err)
+		}
+		return nil
+	},
+}
+
+// The spec of a KubernetesCluster has the format of kubernetes create --config-file
+var clusterManifestKind = manifestKind{
+	newSpec: func() interface{} { return &gobizfly.ClusterCreateRequest{} },
+	normalize: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) error {
+		spec := res.spec.(*gobizfly.ClusterCreateRequest)
+		spec.Name = res.Metadata.Name
+		for key, value := range res.Metadata.Labels {
+			if !hasLabels(spec.Tags, map[string]string{key: value}) {
+				spec.Tags = append(spec.Tags, key+"="+value)
+			}
+		}
+		sort.Strings(spec.Tags)
+		var err error
+		spec.VPCNetworkID, err = resolveReference(ctx, client, spec.VPCNetworkID, resolveVPC)
+		return err
+	},
+	find: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (*liveResource, error) {
+		clusters, err := client.KubernetesEngine.List(ctx, &gobizfly.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]manifestCandidate, 0, len(clusters))
+		for _, cluster := range clusters {
+			candidates = append(candidates, manifestCandidate{id: cluster.UID, name: cluster.Name, tags: cluster.Tags})
+		}
+		id, err := matchManifest(res, candidates)
+		if err != nil || id == "" {
+			return nil, err
+		}
+		cluster, err := client.KubernetesEngine.Get(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		spec := &gobizfly.ClusterCreateRequest{Name: cluster.Name, VPCNetworkID: cluster.VPCNetworkID}
+		for _, pool := range cluster.WorkerPools {
+			spec.WorkerPools = append(spec.WorkerPools, gobizfly.WorkerPool{
+				Name:              pool.Name,
+				DesiredSize:       pool.DesiredSize,
+				EnableAutoScaling: pool.EnableAutoScaling,
+				MinSize:           pool.MinSize,
+				MaxSize:           pool.MaxSize,
+			})
+		}
+		return &liveResource{id: id, spec: spec}, nil
+	},
+	diff: func(desired, live interface{}) []fieldChange {
+		desiredSpec := desired.(*gobizfly.ClusterCreateRequest)
+		liveSpec := live.(*gobizfly.ClusterCreateRequest)
+		changes := compareFields(desiredSpec, liveSpec, nil, "vpc_network_id")
+		for _, pool := range desiredSpec.WorkerPools {
+			field := "worker_pools[" + pool.Name + "]"
+			var current *gobizfly.WorkerPool
+			for i := range liveSpec.WorkerPools {
+				if liveSpec.WorkerPools[i].Name == pool.Name {
+					current = &liveSpec.WorkerPools[i]
+				}
+			}
+			if current == nil {
+				changes = append(changes, fieldChange{Field: field, To: pool.Flavor + " x" + strconv.Itoa(pool.DesiredSize), Updatable: true})
+				continue
+			}
+			fields := []string{"desired_size", "enable_autoscaling", "min_size", "max_size"}
+			for _, change := range compareFields(pool, *current, fields, fields...) {
+				change.Field = field + "." + change.Field
+				changes = append(changes, change)
+			}
+		}
+		return changes
+	},
+	create: func(ctx context.Context, client *gobizfly.Client, res *manifestResource) (string, error) {
+		spec := *res.spec.(*gobizfly.ClusterCreateRequest)
+		var err error
+		if spec.VPCNetworkID, err = resolveVPC(ctx, client, spec.VPCNetworkID); err != nil {
+			return "", err
+		}
+		cluster, err := client.KubernetesEngine.Create(ctx, &spec)
+		if err != nil {
+			return "", err
+		}
+		return cluster.UID, waitForStatus(ctx, res.String(), clusterStatus(client, cluster.UID), clusterReadyStatus...)
+	},
+	update: func(ctx context.Context, client *gobizfly.Client, res *manifestResource, live *liveResource, changes []fieldChange) error {
+		spec := res.spec.(*gobizfly.ClusterCreateRequest)
+		cluster, err := client.KubernetesEngine.Get(ctx, live.id)
+		if err != nil {
+			return err
+		}
+		poolIDs := make(map[string]string)
+		for _, pool := range cluster.WorkerPools {
+			poolIDs[pool.Name] = pool.UID
+		}
+		var newPools []gobizfly.WorkerPool
+		for _, pool := range spec.WorkerPools {
+			field := "worker_pools[" + pool.Name + "]"
+			poolID, ok := poolIDs[pool.Name]
+			if !ok {
+				if hasChange(changes, field) {
+					newPools = append(newPools, pool)
+				}
+				continue
+			}
+			changed := false
+			for _, change := range changes {
+				if strings.HasPrefix(change.Field, field+".") {
+					changed = true
+				}
+			}
+			if !changed {
+				continue
+			}
+			err := client.KubernetesEngine.UpdateClusterWorkerPool(ctx, live.id, poolID, &gobizfly.UpdateWorkerPoolRequest{
+				DesiredSize:       pool.DesiredSize,
+				EnableAutoScaling: pool.EnableAutoScaling,
+				MinSize:           pool.MinSize,
+				MaxSize:           pool.MaxSize,
+			})
+			if err != nil {
+				return fmt.Errorf("Update worker pool %s error: %w", pool.Name, err)
+			}
+			if err := waitForUpdate(ctx, "Worker pool "+pool.Name, workerPoolSizeUpdate(client, live.id, poolID, pool.DesiredSize), workerPoolReadyStatus...); err != nil {
+				return err
+			}
+		}
+		if len(newPools) > 0 {
+			if _, err := client.KubernetesEngine.AddWorkerPools(ctx, live.id, &gobizfly.AddWorkerPoolsRequest{WorkerPools: newPools}); err != nil {
+				return fmt.Errorf("Add worker pools error: %w", err)
+			}
+			return waitForStatus(ctx, res.String(), clusterStatus(client, live.id), clusterReadyStatus...)
+		}
+		return nil
+	},
+}
@@ -18,10 +18,10 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -43,15 +43,18 @@ var networkInterfaceCmd = &cobra.Command{
 	Short: "Bizfly Cloud Network interfaces Interaction",
 	Long:  `Bizfly Cloud Network interfaces Interaction: Create , List, Delete, Update, Action`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Network Interface called")
+		logging.Debugf("Network Interface called")
 	},
 }
 
 var networkInterfaceListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List Network Interfaces",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		opts := gobizfly.ListNetworkInterfaceOptions{
 			VPCNetworkID: vpcNetworkId,
 			Status:       networkInterfaceStatus,
@@ -59,7 +62,7 @@ var networkInterfaceListCmd = &cobra.Command{
 		}
 		networkInterfaces, err := client.CloudServer.NetworkInterfaces().List(ctx, &opts)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		for _, networkInterface := range networkInterfaces {
@@ -76,17 +79,20 @@ var networkInterfaceListCmd = &cobra.Command{
 				networkInterface.UpdatedAt,
 			})
 		}
-		formatter.Output(networkInterfaceHeaders, data)
+		return formatter.Output(networkInterfaceHeaders, data, networkInterfaces)
 	},
 }
 
 var networkInterfaceCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create Network Interface",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args[0]) == 0 {
-			log.Fatal("Please provide Network Interface ID")
+			return usageError("Please provide Network Interface ID")
 		}
 		payload := gobizfly.CreateNetworkInterfacePayload{
 			Name:           networkInterfaceName,
@@ -95,7 +101,7 @@ var networkInterfaceCreateCmd = &cobra.Command{
 		}
 		networkInterface, err := client.CloudServer.NetworkInterfaces().Create(ctx, args[0], &payload)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -110,21 +116,24 @@ var networkInterfaceCreateCmd = &cobra.Command{
 			networkInterface.CreatedAt,
 			networkInterface.UpdatedAt,
 		})
-		formatter.Output(networkInterfaceHeaders, data)
+		return formatter.Output(networkInterfaceHeaders, data, networkInterface)
 	},
 }
 
 var networkInterfaceGetCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get Network Interface",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please provide Network Interface ID")
+			return usageError("Please provide Network Interface ID")
 		}
 		networkInterface, err := client.CloudServer.NetworkInterfaces().Get(ctx, args[0])
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -139,23 +148,27 @@ var networkInterfaceGetCmd = &cobra.Command{
 			networkInterface.CreatedAt,
 			networkInterface.UpdatedAt,
 		})
-		formatter.Output(networkInterfaceHeaders, data)
+		return formatter.Output(networkInterfaceHeaders, data, networkInterface)
 	},
 }
 
 var networkInterfaceDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete Network Interface",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please provide Network Interface ID")
+			return usageError("Please provide Network Interface ID")
 		}
-		err := client.CloudServer.NetworkInterfaces().Delete(ctx, args[0])
+		err = client.CloudServer.NetworkInterfaces().Delete(ctx, args[0])
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
-		fmt.Println("The Network Interface deleted successfully")
+		logging.Infof("The Network Interface deleted successfully")
+		return nil
 	},
 }
 
@@ -164,10 +177,13 @@ var networkInterfaceAddFirewall = &cobra.Command{
 	Short: "Add Firewall to the Network Interface",
 	Long: `Add Firewall to the Network Interface: 
 ./bizfly network-interface add-firewalls <network-interface-id> --firewall <firewall-id> --firewall <firewall-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please provide Network Interface ID")
+			return usageError("Please provide Network Interface ID")
 		}
 		payload := gobizfly.ActionNetworkInterfacePayload{
 			Action:         "add_firewall",
@@ -175,7 +191,7 @@ var networkInterfaceAddFirewall = &cobra.Command{
 		}
 		networkInterface, err := client.CloudServer.NetworkInterfaces().Action(ctx, args[0], &payload)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -190,7 +206,7 @@ var networkInterfaceAddFirewall = &cobra.Command{
 			networkInterface.CreatedAt,
 			networkInterface.UpdatedAt,
 		})
-		formatter.Output(networkInterfaceHeaders, data)
+		return formatter.Output(networkInterfaceHeaders, data, networkInterface)
 	},
 }
 
@@ -199,10 +215,13 @@ var networkInterfaceRemoveFirewall = &cobra.Command{
 	Short: "Remove Firewall from the Network Interface",
 	Long: `Remove Firewall from the Network Interface: 
 ./bizfly network-interface remove-firewalls <network-interface-id> --firewall <firewall-id> --firewall <firewall_id>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please provide Network Interface ID")
+			return usageError("Please provide Network Interface ID")
 		}
 		payload := gobizfly.ActionNetworkInterfacePayload{
 			Action:         "remove_firewall",
@@ -210,7 +229,7 @@ var networkInterfaceRemoveFirewall = &cobra.Command{
 		}
 		networkInterface, err := client.CloudServer.NetworkInterfaces().Action(ctx, args[0], &payload)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -225,7 +244,7 @@ var networkInterfaceRemoveFirewall = &cobra.Command{
 			networkInterface.CreatedAt,
 			networkInterface.UpdatedAt,
 		})
-		formatter.Output(networkInterfaceHeaders, data)
+		return formatter.Output(networkInterfaceHeaders, data, networkInterface)
 	},
 }
 
@@ -234,10 +253,13 @@ var networkInterfaceAttachServer = &cobra.Command{
 	Short: "Attach Server to the Network Interface",
 	Long: `Attach Server to the Network Interface: 
 ./bizfly network-interface attach-server <network-interface-id> --server-id <server_id>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please provide Network Interface ID")
+			return usageError("Please provide Network Interface ID")
 		}
 		payload := gobizfly.ActionNetworkInterfacePayload{
 			Action:   "attach_server",
@@ -245,7 +267,7 @@ var networkInterfaceAttachServer = &cobra.Command{
 		}
 		networkInterface, err := client.CloudServer.NetworkInterfaces().Action(ctx, args[0], &payload)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -260,7 +282,7 @@ var networkInterfaceAttachServer = &cobra.Command{
 			networkInterface.CreatedAt,
 			networkInterface.UpdatedAt,
 		})
-		formatter.Output(networkInterfaceHeaders, data)
+		return formatter.Output(networkInterfaceHeaders, data, networkInterface)
 	},
 }
 
@@ -269,17 +291,20 @@ var networkInterfaceDetachServer = &cobra.Command{
 	Short: "Detach Server from the Network Interface",
 	Long: `Detach Server from the Network Interface:
 ./bizfly network-interface detach-server <network-interface-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please provide Network Interface ID")
+			return usageError("Please provide Network Interface ID")
 		}
 		payload := gobizfly.ActionNetworkInterfacePayload{
 			Action: "detach_server",
 		}
 		networkInterface, err := client.CloudServer.NetworkInterfaces().Action(ctx, args[0], &payload)
 		if err != nil {
-			log.Fatalln(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -294,7 +319,7 @@ var networkInterfaceDetachServer = &cobra.Command{
 			networkInterface.CreatedAt,
 			networkInterface.UpdatedAt,
 		})
-		formatter.Output(networkInterfaceHeaders, data)
+		return formatter.Output(networkInterfaceHeaders, data, networkInterface)
 	},
 }
 
@@ -0,0 +1,237 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bizflycloud/gobizfly"
+)
+
+// uuidPattern matches a full resource ID, which is used as is without a lookup
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// resourceRef is a resource which can be resolved by its ID or one of its names
+type resourceRef struct {
+	id    string
+	names []string
+}
+
+func (r resourceRef) String() string {
+	if len(r.names) == 0 || r.names[0] == "" {
+		return r.id
+	}
+	return fmt.Sprintf("%s (%s)", r.id, r.names[0])
+}
+
+// resourceKind lists the resources of a kind for the resolver and the shell completion
+type resourceKind struct {
+	name string
+	list func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error)
+}
+
+var (
+	serverKind = resourceKind{name: "server", list: func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error) {
+		servers, err := client.CloudServer.List(ctx, &gobizfly.ServerListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		refs := make([]resourceRef, 0, len(servers))
+		for _, server := range servers {
+			refs = append(refs, resourceRef{id: server.ID, names: []string{server.Name}})
+		}
+		return refs, nil
+	}}
+	volumeKind = resourceKind{name: "volume", list: func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error) {
+		volumes, err := client.CloudServer.Volumes().List(ctx, &gobizfly.VolumeListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		refs := make([]resourceRef, 0, len(volumes))
+		for _, volume := range volumes {
+			refs = append(refs, resourceRef{id: volume.ID, names: []string{volume.Name}})
+		}
+		return refs, nil
+	}}
+	vpcKind = resourceKind{name: "VPC", list: func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error) {
+		vpcs, err := client.CloudServer.VPCNetworks().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		refs := make([]resourceRef, 0, len(vpcs))
+		for _, vpc := range vpcs {
+			refs = append(refs, resourceRef{id: vpc.ID, names: []string{vpc.Name}})
+		}
+		return refs, nil
+	}}
+	firewallKind = resourceKind{name: "firewall", list: func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error) {
+		firewalls, err := client.CloudServer.Firewalls().List(ctx, &gobizfly.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		refs := make([]resourceRef, 0, len(firewalls))
+		for _, firewall := range firewalls {
+			refs = append(refs, resourceRef{id: firewall.ID, names: []string{firewall.Name}})
+		}
+		return refs, nil
+	}}
+	loadBalancerKind = resourceKind{name: "load balancer", list: func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error) {
+		lbs, err := client.CloudLoadBalancer.List(ctx, &gobizfly.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		refs := make([]resourceRef, 0, len(lbs))
+		for _, lb := range lbs {
+			refs = append(refs, resourceRef{id: lb.ID, names: []string{lb.Name}})
+		}
+		return refs, nil
+	}}
+	clusterKind = resourceKind{name: "cluster", list: func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error) {
+		clusters, err := client.KubernetesEngine.List(ctx, &gobizfly.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		refs := make([]resourceRef, 0, len(clusters))
+		for _, cluster := range clusters {
+			refs = append(refs, resourceRef{id: cluster.UID, names: []string{cluster.Name}})
+		}
+		return refs, nil
+	}}
+	// SSH keys have no ID, they are matched by their name or a unique name prefix
+	sshKeyKind = resourceKind{name: "SSH key", list: func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error) {
+		keys, err := client.CloudServer.SSHKeys().List(ctx, &gobizfly.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		refs := make([]resourceRef, 0, len(keys))
+		for _, key := range keys {
+			refs = append(refs, resourceRef{id: key.SSHKeyPair.Name})
+		}
+		return refs, nil
+	}}
+	// WAN IPs are also matched by their IP address
+	wanIPKind = resourceKind{name: "WAN IP", list: func(ctx context.Context, client *gobizfly.Client) ([]resourceRef, error) {
+		wanIPs, err := client.CloudServer.PublicNetworkInterfaces().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		refs := make([]resourceRef, 0, len(wanIPs))
+		for _, wanIP := range wanIPs {
+			refs = append(refs, resourceRef{id: wanIP.ID, names: []string{wanIP.Name, wanIP.IPAddress}})
+		}
+		return refs, nil
+	}}
+)
+
+// resolve returns the ID of the resource matching value, which is an ID, a
+// name or a unique ID prefix, in this order of precedence
+func resolve(ctx context.Context, client *gobizfly.Client, kind resourceKind, value string) (string, error) {
+	if value == "" {
+		return "", usageError("You need to specify the %s", kind.name)
+	}
+	if uuidPattern.MatchString(value) {
+		return value, nil
+	}
+	refs, err := kind.list(ctx, client)
+	if err != nil {
+		return "", fmt.Errorf("List %ss error: %w", kind.name, err)
+	}
+	var byName, byPrefix []resourceRef
+	for _, ref := range refs {
+		if ref.id == value {
+			return ref.id, nil
+		}
+		for _, name := range ref.names {
+			if name == value {
+				byName = append(byName, ref)
+				break
+			}
+		}
+		if strings.HasPrefix(ref.id, value) {
+			byPrefix = append(byPrefix, ref)
+		}
+	}
+	for _, matches := range [][]resourceRef{byName, byPrefix} {
+		switch {
+		case len(matches) == 1:
+			return matches[0].id, nil
+		case len(matches) > 1:
+			return "", ambiguousError(kind.name, value, matches)
+		}
+	}
+	return "", notFoundError("No %s matches %q", kind.name, value)
+}
+
+func ambiguousError(kind, value string, matches []resourceRef) error {
+	candidates := make([]string, 0, len(matches))
+	for _, ref := range matches {
+		candidates = append(candidates, "  "+ref.String())
+	}
+	sort.Strings(candidates)
+	return usageError("%q matches more than one %s, use one of the IDs:\n%s", value, kind, strings.Join(candidates, "\n"))
+}
+
+// resolveAll resolves a list of values with the same resolver
+func resolveAll(ctx context.Context, client *gobizfly.Client, values []string,
+	resolver func(ctx context.Context, client *gobizfly.Client, value string) (string, error)) ([]string, error) {
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		id, err := resolver(ctx, client, value)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func resolveServer(ctx context.Context, client *gobizfly.Client, value string) (string, error) {
+	return resolve(ctx, client, serverKind, value)
+}
+
+func resolveVolume(ctx context.Context, client *gobizfly.Client, value string) (string, error) {
+	return resolve(ctx, client, volumeKind, value)
+}
+
+func resolveVPC(ctx context.Context, client *gobizfly.Client, value string) (string, error) {
+	return resolve(ctx, client, vpcKind, value)
+}
+
+func resolveFirewall(ctx context.Context, client *gobizfly.Client, value string) (string, error) {
+	return resolve(ctx, client, firewallKind, value)
+}
+
+func resolveLoadBalancer(ctx context.Context, client *gobizfly.Client, value string) (string, error) {
+	return resolve(ctx, client, loadBalancerKind, value)
+}
+
+func resolveCluster(ctx context.Context, client *gobizfly.Client, value string) (string, error) {
+	return resolve(ctx, client, clusterKind, value)
+}
+
+// resolveSSHKey returns the name of an SSH key
+func resolveSSHKey(ctx context.Context, client *gobizfly.Client, value string) (string, error) {
+	return resolve(ctx, client, sshKeyKind, value)
+}
+
+func resolveWanIP(ctx context.Context, client *gobizfly.Client, value string) (string, error) {
+	return resolve(ctx, client, wanIPKind, value)
+}
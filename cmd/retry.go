@@ -0,0 +1,143 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/bizflycloud/bizflyctl/logging"
+)
+
+const (
+	defaultRequestTimeout = time.Minute
+	defaultMaxRetries     = 3
+	minRetryBackoff       = 500 * time.Millisecond
+	maxRetryBackoff       = 10 * time.Second
+)
+
+var requestTimeout time.Duration
+
+// apiTimeout returns the timeout of an API request of --timeout, or of the
+// timeout config key when the flag is not set
+func apiTimeout() (time.Duration, error) {
+	if rootCmd.PersistentFlags().Changed("timeout") {
+		return requestTimeout, nil
+	}
+	if v := configValue("timeout"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return 0, usageError("Invalid timeout %q, use a duration such as 30s or 2m", v)
+		}
+		return d, nil
+	}
+	return requestTimeout, nil
+}
+
+// retryTransport limits each request to timeout and retries the idempotent
+// requests which fail with a network error, 429 or a 5xx status, with an
+// exponential backoff and jitter
+type retryTransport struct {
+	base       http.RoundTripper
+	timeout    time.Duration
+	maxRetries int
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	for attempt := 0; ; attempt++ {
+		resp, err := t.send(req)
+		if !idempotent || attempt >= t.maxRetries || !retryable(resp, err) || req.Context().Err() != nil {
+			return resp, err
+		}
+		delay := retryDelay(attempt, resp)
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBody))
+			_ = resp.Body.Close()
+		}
+		logging.Verbosef("%s %s failed: %s, retrying in %v", req.Method, req.URL.Path, reason, delay.Round(time.Millisecond))
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// send sends one attempt of req, canceled after the timeout
+func (t *retryTransport) send(req *http.Request) (*http.Response, error) {
+	if t.timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.timeout)
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// the body is read after RoundTrip returns, cancel when it is closed
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// retryable reports whether a failed attempt is worth retrying
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// retryDelay returns the delay before the next attempt, the Retry-After of a
+// 429 or an exponential backoff with jitter
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			if d := time.Duration(seconds) * time.Second; d <= maxRetryBackoff {
+				return d
+			}
+			return maxRetryBackoff
+		}
+	}
+	backoff := minRetryBackoff << uint(attempt)
+	if backoff > maxRetryBackoff || backoff <= 0 {
+		backoff = maxRetryBackoff
+	}
+	// between half and all of the backoff, so that clients do not retry together
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
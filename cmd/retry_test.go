@@ -0,0 +1,112 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingServer answers the first failures requests with status, then 200
+func failingServer(t *testing.T, failures int32, status int, delay time.Duration) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= failures {
+			time.Sleep(delay)
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		failures   int32
+		status     int
+		delay      time.Duration
+		wantStatus int
+		wantCalls  int32
+		wantErr    bool
+	}{
+		{name: "GET retried on 503", method: http.MethodGet, failures: 2, status: 503, wantStatus: 200, wantCalls: 3},
+		{name: "GET retried on 429", method: http.MethodGet, failures: 1, status: 429, wantStatus: 200, wantCalls: 2},
+		{name: "GET gives up", method: http.MethodGet, failures: 10, status: 502, wantStatus: 502, wantCalls: 4},
+		{name: "GET not retried on 404", method: http.MethodGet, failures: 1, status: 404, wantStatus: 404, wantCalls: 1},
+		{name: "POST not retried", method: http.MethodPost, failures: 1, status: 503, wantStatus: 503, wantCalls: 1},
+		{name: "GET retried after a timeout", method: http.MethodGet, failures: 1, status: 200, delay: 300 * time.Millisecond,
+			wantStatus: 200, wantCalls: 2},
+		{name: "POST timeout", method: http.MethodPost, failures: 1, status: 200, delay: 300 * time.Millisecond,
+			wantCalls: 1, wantErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			server, calls := failingServer(t, tc.failures, tc.status, tc.delay)
+			client := &http.Client{Transport: &retryTransport{base: http.DefaultTransport, timeout: 100 * time.Millisecond,
+				maxRetries: defaultMaxRetries}}
+			req, err := http.NewRequest(tc.method, server.URL, strings.NewReader(""))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if tc.wantErr {
+				if err == nil || !errors.Is(err, context.DeadlineExceeded) {
+					t.Fatalf("got %v, want a timeout", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				_ = resp.Body.Close()
+				if resp.StatusCode != tc.wantStatus {
+					t.Errorf("got status %d, want %d", resp.StatusCode, tc.wantStatus)
+				}
+			}
+			if got := atomic.LoadInt32(calls); got != tc.wantCalls {
+				t.Errorf("got %d requests, want %d", got, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestStatusTransportClearsRecoveredFailure(t *testing.T) {
+	server, _ := failingServer(t, 1, http.StatusUnauthorized, 0)
+	st := &statusTransport{base: http.DefaultTransport}
+	for _, want := range []int{http.StatusUnauthorized, 0} {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := st.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+		if status, _ := st.last(); status != want {
+			t.Errorf("got status %d, want %d", status, want)
+		}
+	}
+}
@@ -18,13 +18,14 @@ package cmd
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"errors"
+	"net/http"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/bizflycloud/bizflyctl/constants"
+	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
@@ -39,29 +40,54 @@ var (
 	project_id    string
 	appCredSecret string
 	appCredID     string
+	outputFormat  string
+	profileName   string
+	verbose       bool
+	debug         bool
 )
 
+// wideAnnotation marks the commands which print additional columns with -o wide
+const wideAnnotation = "wide"
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "bizfly",
 	Short: "Bizfly Cloud Command Line",
 	Long:  `Bizfly Cloud Command Line`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if formatter.IsWide() && cmd.Annotations[wideAnnotation] == "" {
+			return usageError("%s has no additional columns for -o wide, use -o table, json, yaml or csv", cmd.CommandPath())
+		}
+		return nil
+	},
 	PreRun: func(cmd *cobra.Command, args []string) {
-		fmt.Println("Pre run")
+		logging.Debugf("Pre run")
 	},
+	// errors are printed by Execute with their exit code
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	registerCompletions()
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		if !errors.Is(err, errSilent) {
+			logging.Errorf("%v", err)
+		}
+		os.Exit(exitCode(err))
+	}
+	if dryRun {
+		logging.Infof("Dry run, no changes were made")
 	}
 }
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return usageError("%v\nRun '%s --help' for usage", err, cmd.CommandPath())
+	})
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
@@ -77,6 +103,24 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&region, "region", "HaNoi", "Region you want to access the resource. Read environment variable BIZFLY_CLOUD_REGION")
 	rootCmd.PersistentFlags().StringVar(&project_id, "project-id", "", "Your Bizfly Cloud Project ID. Read environment variable BIZFLY_CLOUD_PROJECT_ID")
 
+	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", "", "Bizfly Cloud API URL, default "+defaultAPIURL+". Read environment variable BIZFLY_CLOUD_API_URL")
+	rootCmd.PersistentFlags().StringVar(&authURL, "auth-url", "", "Bizfly Cloud login page URL, default "+defaultAuthURL+". Read environment variable BIZFLY_CLOUD_AUTH_URL")
+	rootCmd.PersistentFlags().StringVar(&caCertFile, "ca-cert", "", "PEM file of a CA to trust for the API. Read environment variable BIZFLY_CLOUD_CA_CERT")
+	rootCmd.PersistentFlags().BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "Do not verify the TLS certificate of the API. Read environment variable BIZFLY_CLOUD_INSECURE_SKIP_VERIFY")
+
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Profile in the config file to use. Read environment variable BIZFLY_CLOUD_PROFILE")
+
+	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", formatter.TableFormat,
+		"Output format: "+strings.Join(formatter.SupportedFormats, "|")+"|jsonpath=<template>|go-template=<template>")
+
+	rootCmd.PersistentFlags().DurationVar(&requestTimeout, "timeout", defaultRequestTimeout,
+		"Maximum time of each API request, 0 for no limit. Read environment variable BIZFLY_CLOUD_TIMEOUT")
+
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the requests which would change resources instead of sending them")
+
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "Print detailed diagnostics to stderr")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Print debug diagnostics to stderr, including the HTTP requests and responses")
+
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
@@ -84,6 +128,13 @@ func init() {
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
+	switch {
+	case debug:
+		logging.SetLevel(logging.DebugLevel)
+	case verbose:
+		logging.SetLevel(logging.VerboseLevel)
+	}
+
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
@@ -91,8 +142,8 @@ func initConfig() {
 		// Find home directory.
 		home, err := homedir.Dir()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			logging.Errorf("%v", err)
+			os.Exit(ExitError)
 		}
 
 		// Search config in home directory with name ".bizfly" (without extension).
@@ -100,12 +151,17 @@ func initConfig() {
 		viper.SetConfigName(".bizfly")
 	}
 
+	if err := formatter.SetFormat(outputFormat); err != nil {
+		logging.Errorf("%v", err)
+		os.Exit(ExitUsage)
+	}
+
 	viper.SetEnvPrefix("BIZFLY_CLOUD")
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		logging.Verbosef("Using config file: %s", viper.ConfigFileUsed())
 	}
 }
 
@@ -115,86 +171,171 @@ func getRegionName(regionName string) string {
 	return result
 }
 
-func getApiClient(cmd *cobra.Command) (*gobizfly.Client, context.Context) {
+// activeRegionName returns the region of --region, or of the profile when the
+// flag is not set
+func activeRegionName(cmd *cobra.Command) string {
+	if !cmd.Flags().Changed("region") {
+		if r := configValue("region"); r != "" {
+			region = r
+		}
+	}
+	return getRegionName(region)
+}
+
+func getApiClient(cmd *cobra.Command) (*gobizfly.Client, context.Context, error) {
+	client, ctx, _, err := getApiClientToken(cmd)
+	return client, ctx, err
+}
+
+// getApiClientToken returns an authenticated client and the token in use
+func getApiClientToken(cmd *cobra.Command) (*gobizfly.Client, context.Context, *gobizfly.Token, error) {
+	activeProfile := getActiveProfile()
+	if activeProfile != defaultProfile && !viper.IsSet(profilesKey+"."+activeProfile) {
+		return nil, nil, nil, usageError("Profile %s is not found. Use: bizfly config profile add %s", activeProfile, activeProfile)
+	}
 	// use application credential auth
 	if appCredID == "" {
-		appCredID = viper.GetString("app_credential_id")
+		appCredID = configValue("app_credential_id")
 	}
 	if appCredSecret == "" {
-		appCredSecret = viper.GetString("app_credential_secret")
+		secret, err := secretValue("app_credential_secret")
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		appCredSecret = secret
 	}
 	useAppCredential := true
 	if appCredID == "" && appCredSecret == "" {
 		// use username/password auth
 		if email == "" {
-			email = viper.GetString("email")
+			email = configValue("email")
 		}
 		if password == "" {
-			password = viper.GetString("password")
+			secret, err := secretValue("password")
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			password = secret
 		}
 		useAppCredential = false
 	}
 
 	// Check for stored token
-	authToken := viper.GetString("auth_token")
-
-
-	if viper.GetString("region") != "" {
-		region = viper.GetString("region")
+	authToken, err := secretValue("auth_token")
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	regionName := getRegionName(region)
+	regionName := activeRegionName(cmd)
 	if regionName == "" {
-		log.Fatalf("Invalid region %s", region)
+		return nil, nil, nil, usageError("Invalid region %s", region)
 	}
 
-	if viper.GetString("project_id") != "" {
-		project_id = viper.GetString("project_id")
+	if project_id == "" {
+		project_id = configValue("project_id")
+	}
+	endpoint, err := apiEndpoint()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	timeout, err := apiTimeout()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	httpTransport, err := newHTTPTransport()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	logging.Verbosef("Using profile %s, region %s", activeProfile, regionName)
+	apiStatus.base = &retryTransport{base: logging.NewTransport(httpTransport), timeout: timeout, maxRetries: defaultMaxRetries}
+	transport := &reauthTransport{base: apiStatus}
+	var roundTripper http.RoundTripper = transport
+	if dryRun {
+		roundTripper = &dryRunTransport{base: transport}
+	}
+	options := []gobizfly.Option{gobizfly.WithProjectID(project_id), gobizfly.WithRegionName(regionName),
+		gobizfly.WithHTTPClient(&http.Client{Transport: roundTripper})}
+	// the API endpoint can be overridden for staging, on-premise deployments or a fake API
+	if endpoint != defaultAPIURL {
+		logging.Verbosef("Using the API at %s", endpoint)
+		options = append(options, gobizfly.WithAPIUrl(endpoint))
 	}
 	// nolint:staticcheck
-	client, err := gobizfly.NewClient(gobizfly.WithProjectID(project_id), gobizfly.WithRegionName(regionName))
+	client, err := gobizfly.NewClient(options...)
 
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, nil, err
 	}
-	ctx, cancelFunc := context.WithTimeout(context.Background(), time.Second*10)
-	defer cancelFunc()
+	// the requests are limited by --timeout, the context lives as long as the command
+	ctx := context.Background()
 
 	var tok *gobizfly.Token
 	if authToken != "" {
 		// Use stored token
 		tcr := &gobizfly.TokenCreateRequest{
-			Token: authToken,
-			ProjectID:     project_id, // We might need to fetch project ID if not provided, but for now assume it's set or not needed for initial client creation if token is valid?
+			Token:     authToken,
+			ProjectID: project_id, // We might need to fetch project ID if not provided, but for now assume it's set or not needed for initial client creation if token is valid?
 			// Actually, gobizfly.Token struct has ProjectID.
 		}
+		logging.Verbosef("Using the token stored in the config file")
 		tok, err = client.Token.Init(ctx, tcr)
 		if err != nil {
-			log.Fatal(err)
+			return nil, nil, nil, authError("The stored token is invalid: %v. Use: bizfly login", err)
 		}
 		// If project_id is empty, we might want to try to inspect the token or just proceed.
 		// However, NewClient already took project_id.
 	} else {
-		//TODO Get token from cache
 		request := &gobizfly.TokenCreateRequest{
 			ProjectID: project_id,
 		}
+		identity := email
 		if useAppCredential {
 			request.AuthType = "app_credential"
 			request.AppCredID = appCredID
 			request.AppCredSecret = appCredSecret
+			identity = appCredID
 		} else {
 			request.AuthMethod = "password"
 			request.Username = email
 			request.Password = password
 		}
-		tok, err = client.Token.Create(ctx, request)
-		if err != nil {
-			log.Fatal(err)
+		cacheIdentity := identity
+		if endpoint != defaultAPIURL {
+			// the tokens of another endpoint are not valid for this one
+			cacheIdentity += "@" + endpoint
+		}
+		cache := newTokenCache(activeProfile, regionName, project_id, cacheIdentity)
+		tok = cache.Load()
+		if tok != nil {
+			logging.Verbosef("Using cached token of %s", identity)
+			// the cached token may have been revoked, authenticate again if the API rejects it
+			transport.refresh = func(ctx context.Context) (string, error) {
+				newTok, err := client.Token.Create(ctx, request)
+				if err != nil {
+					return "", err
+				}
+				client.SetKeystoneToken(newTok)
+				if err := cache.Save(newTok); err != nil {
+					logging.Warnf("failed to cache token: %v", err)
+				}
+				return newTok.KeystoneToken, nil
+			}
+		} else {
+			logging.Verbosef("Authenticating as %s", identity)
+			tok, err = client.Token.Create(ctx, request)
+			if err != nil {
+				if exitCode(err) == ExitAPI {
+					return nil, nil, nil, err
+				}
+				return nil, nil, nil, authError("Authentication failed: %v", err)
+			}
+			if err := cache.Save(tok); err != nil {
+				logging.Warnf("failed to cache token: %v", err)
+			}
 		}
 	}
 
 	client.SetKeystoneToken(tok)
 	ctx = context.WithValue(ctx, "token", tok.KeystoneToken)
-	return client, ctx
+	return client, ctx, tok, nil
 }
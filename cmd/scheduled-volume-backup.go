@@ -18,9 +18,9 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -39,18 +39,21 @@ var scheduledVolumeBackupCmd = &cobra.Command{
 	Short: "Bizfly Cloud Scheduled Volume Backup",
 	Long:  `Bizfly Cloud Scheduled Volume Backup Action: Create, List, Get, Delete, Update`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("schedule-volume-backup called")
+		logging.Debugf("schedule-volume-backup called")
 	},
 }
 
 var scheduledVolumeBackupListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List scheduled volume backup",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		backups, err := client.CloudServer.ScheduledVolumeBackups().List(ctx)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, key := range backups {
@@ -65,23 +68,26 @@ var scheduledVolumeBackupListCmd = &cobra.Command{
 				key.CreatedAt,
 			})
 		}
-		formatter.Output(scheduledVolumeBackupHeader, data)
+		return formatter.Output(scheduledVolumeBackupHeader, data, backups)
 	},
 }
 
 var scheduledVolumeBackupGetCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get scheduled volume backup",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please specify backup id")
+			return usageError("Please specify backup id")
 		} else if len(args) > 1 {
-			log.Fatal("Too many arguments")
+			return usageError("Too many arguments")
 		}
 		backup, err := client.CloudServer.ScheduledVolumeBackups().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -94,7 +100,7 @@ var scheduledVolumeBackupGetCmd = &cobra.Command{
 			backup.BillingPlan,
 			backup.CreatedAt,
 		})
-		formatter.Output(scheduledVolumeBackupHeader, data)
+		return formatter.Output(scheduledVolumeBackupHeader, data, backup)
 	},
 }
 
@@ -102,24 +108,27 @@ var scheduleVolumeBackupCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create scheduled volume backup",
 	Long:  "Create scheduled volume backup: bizfly schedule-volume-backup create <volume_id> --frequency=<frequency> --size=<size> --hour=<hour>",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please specify volume id")
+			return usageError("Please specify volume id")
 		} else if len(args) > 1 {
-			log.Fatal("Too many arguments")
+			return usageError("Too many arguments")
 		}
 		volumeId = args[0]
 		if len(frequency) == 0 {
-			log.Fatal("Please specify frequency")
+			return usageError("Please specify frequency")
 		}
 		if len(size) == 0 {
-			log.Fatal("Please specify size")
+			return usageError("Please specify size")
 		}
 		if hour == -1 {
 			hour = 0
 		} else if hour < 0 || hour > 23 {
-			log.Fatal("Invalid hour")
+			return usageError("Invalid hour")
 		}
 		payload := &gobizfly.CreateBackupPayload{
 			ResourceID: volumeId,
@@ -129,7 +138,7 @@ var scheduleVolumeBackupCreateCmd = &cobra.Command{
 		}
 		backup, err := client.CloudServer.ScheduledVolumeBackups().Create(ctx, payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -142,7 +151,7 @@ var scheduleVolumeBackupCreateCmd = &cobra.Command{
 			backup.BillingPlan,
 			backup.CreatedAt,
 		})
-		formatter.Output(scheduledVolumeBackupHeader, data)
+		return formatter.Output(scheduledVolumeBackupHeader, data, backup)
 	},
 }
 
@@ -150,18 +159,22 @@ var scheduledVolumeBackupDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete scheduled volume backup",
 	Long:  "Delete backup: bizfly schedule-volume-backup delete <backup_id>",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please specify backup id")
+			return usageError("Please specify backup id")
 		} else if len(args) > 1 {
-			log.Fatal("Too many arguments")
+			return usageError("Too many arguments")
 		}
-		err := client.CloudServer.ScheduledVolumeBackups().Delete(ctx, args[0])
+		err = client.CloudServer.ScheduledVolumeBackups().Delete(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Backup deleted")
+		logging.Infof("Backup deleted")
+		return nil
 	},
 }
 
@@ -169,16 +182,19 @@ var scheduledVolumeBackupUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update scheduled volume backup",
 	Long:  "Update backup: bizfly scheduled-volume-backup update <backup_id> --frequency=<frequency> --size=<size> --hour=<hour>",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		if len(args) == 0 {
-			log.Fatal("Please specify backup id")
+			return usageError("Please specify backup id")
 		} else if len(args) > 1 {
-			log.Fatal("Too many arguments")
+			return usageError("Too many arguments")
 		}
 		backup, err := client.CloudServer.ScheduledVolumeBackups().Get(ctx, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		payload := gobizfly.UpdateBackupPayload{}
 		if frequency != "" {
@@ -195,7 +211,7 @@ var scheduledVolumeBackupUpdateCmd = &cobra.Command{
 
 		backup, err = client.CloudServer.ScheduledVolumeBackups().Update(ctx, args[0], &payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 
 		var data [][]string
@@ -209,7 +225,7 @@ var scheduledVolumeBackupUpdateCmd = &cobra.Command{
 			backup.BillingPlan,
 			backup.CreatedAt,
 		})
-		formatter.Output(scheduledVolumeBackupHeader, data)
+		return formatter.Output(scheduledVolumeBackupHeader, data, backup)
 	},
 }
 
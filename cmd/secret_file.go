@@ -0,0 +1,218 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// secretPassphraseEnv holds the passphrase of the encrypted secret file
+	secretPassphraseEnv = "BIZFLY_CLOUD_SECRET_PASSPHRASE"
+	secretFileVersion   = 1
+	secretKeyLength     = 32
+)
+
+// secretFileIterations is the PBKDF2 iteration count of the new secret files
+var secretFileIterations = 600000
+
+// encryptedSecrets is the content of the secret file. The secrets are
+// encrypted with AES-256-GCM, with a key derived from the passphrase.
+type encryptedSecrets struct {
+	Version    int    `json:"version"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// fileStore keeps the secrets in a file encrypted with a passphrase, for the
+// systems without a keyring
+type fileStore struct {
+	path string
+
+	loaded     bool
+	salt       []byte
+	iterations int
+	key        []byte
+	// secrets are the secrets by profile and key
+	secrets map[string]map[string]string
+}
+
+// secretFilePath returns the secret file next to the config file, e.g.
+// ~/.bizfly.secrets for ~/.bizfly.yaml
+func secretFilePath() (string, error) {
+	path, err := configFilePath()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".secrets", nil
+}
+
+func (s *fileStore) Name() string {
+	return secretStoreFile
+}
+
+func (s *fileStore) Get(profile, key string) (string, error) {
+	if err := s.load(); err != nil {
+		return "", err
+	}
+	return s.secrets[profile][key], nil
+}
+
+func (s *fileStore) Set(profile, key, value string) error {
+	if err := s.load(); err != nil {
+		return err
+	}
+	if s.secrets[profile] == nil {
+		s.secrets[profile] = map[string]string{}
+	}
+	s.secrets[profile][key] = value
+	if dryRunLocal("store %s of profile %s in %s", key, profile, s.path) {
+		return nil
+	}
+	return s.save()
+}
+
+func (s *fileStore) Delete(profile, key string) (bool, error) {
+	if err := s.load(); err != nil {
+		return false, err
+	}
+	if _, ok := s.secrets[profile][key]; !ok {
+		return false, nil
+	}
+	delete(s.secrets[profile], key)
+	if len(s.secrets[profile]) == 0 {
+		delete(s.secrets, profile)
+	}
+	if dryRunLocal("remove %s of profile %s from %s", key, profile, s.path) {
+		return true, nil
+	}
+	return true, s.save()
+}
+
+// load decrypts the secret file, a missing file has no secrets
+func (s *fileStore) load() error {
+	if s.loaded {
+		return nil
+	}
+	passphrase := os.Getenv(secretPassphraseEnv)
+	if passphrase == "" {
+		return usageError("The file secret store needs a passphrase. Set %s", secretPassphraseEnv)
+	}
+	b, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		s.salt = make([]byte, 16)
+		if _, err := rand.Read(s.salt); err != nil {
+			return err
+		}
+		s.iterations = secretFileIterations
+		if s.key, err = pbkdf2.Key(sha256.New, passphrase, s.salt, s.iterations, secretKeyLength); err != nil {
+			return err
+		}
+		s.secrets = map[string]map[string]string{}
+		s.loaded = true
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read secret file: %w", err)
+	}
+	var file encryptedSecrets
+	if err := json.Unmarshal(b, &file); err != nil {
+		return fmt.Errorf("failed to parse secret file %s: %w", s.path, err)
+	}
+	if file.Version != secretFileVersion {
+		return fmt.Errorf("unsupported version %d of secret file %s", file.Version, s.path)
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, file.Salt, file.Iterations, secretKeyLength)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return authError("Failed to decrypt %s, check %s", s.path, secretPassphraseEnv)
+	}
+	secrets := map[string]map[string]string{}
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return fmt.Errorf("failed to parse secret file %s: %w", s.path, err)
+	}
+	s.salt, s.iterations, s.key, s.secrets, s.loaded = file.Salt, file.Iterations, key, secrets, true
+	return nil
+}
+
+// save encrypts the secrets with a new nonce and replaces the secret file
+func (s *fileStore) save() error {
+	plaintext, err := json.Marshal(s.secrets)
+	if err != nil {
+		return err
+	}
+	gcm, err := newGCM(s.key)
+	if err != nil {
+		return err
+	}
+	file := encryptedSecrets{Version: secretFileVersion, Iterations: s.iterations, Salt: s.salt,
+		Nonce: make([]byte, gcm.NonceSize())}
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return err
+	}
+	file.Ciphertext = gcm.Seal(nil, file.Nonce, plaintext, nil)
+	b, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return err
+	}
+	// write a temporary file first, so that a failure does not lose the secrets
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to save secret file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to save secret file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save secret file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save secret file: %w", err)
+	}
+	return nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
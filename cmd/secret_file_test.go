@@ -0,0 +1,75 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStore(t *testing.T) {
+	iterations := secretFileIterations
+	secretFileIterations = 1000
+	t.Cleanup(func() { secretFileIterations = iterations })
+	path := filepath.Join(t.TempDir(), ".bizfly.secrets")
+
+	t.Setenv(secretPassphraseEnv, "")
+	if _, err := (&fileStore{path: path}).Get("default", "password"); err == nil {
+		t.Fatal("got no error without a passphrase")
+	}
+
+	t.Setenv(secretPassphraseEnv, "correct horse")
+	store := &fileStore{path: path}
+	if err := store.Set("default", "password", "s3cret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Set("staging", "auth_token", "token"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "s3cret") {
+		t.Error("the secret file holds the secret in plain text")
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("got mode %v, %v, want 0600", info.Mode().Perm(), err)
+	}
+
+	// a new store reads the file again
+	store = &fileStore{path: path}
+	if got, err := store.Get("default", "password"); err != nil || got != "s3cret" {
+		t.Errorf("got %q, %v, want s3cret", got, err)
+	}
+	if got, err := store.Get("default", "auth_token"); err != nil || got != "" {
+		t.Errorf("got %q, %v, want nothing", got, err)
+	}
+	if deleted, err := store.Delete("staging", "auth_token"); err != nil || !deleted {
+		t.Errorf("got %v, %v, want deleted", deleted, err)
+	}
+	if deleted, err := store.Delete("staging", "auth_token"); err != nil || deleted {
+		t.Errorf("got %v, %v, want nothing deleted", deleted, err)
+	}
+
+	t.Setenv(secretPassphraseEnv, "wrong")
+	if _, err := (&fileStore{path: path}).Get("default", "password"); err == nil {
+		t.Error("got no error with a wrong passphrase")
+	}
+}
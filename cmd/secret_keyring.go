@@ -0,0 +1,112 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const (
+	// keyringService is the service attribute of the secrets in the keyring
+	keyringService = "bizflyctl"
+	secretTool     = "secret-tool"
+)
+
+var errSecretNotFound = errors.New("secret not found")
+
+// keyringStore keeps the secrets in the Secret Service of the session, e.g.
+// GNOME Keyring or KWallet, through secret-tool of libsecret
+type keyringStore struct{}
+
+// keyringAvailable reports whether secret-tool can be used
+func keyringAvailable() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	_, err := exec.LookPath(secretTool)
+	return err == nil
+}
+
+func (keyringStore) Name() string {
+	return secretStoreKeyring
+}
+
+func (s keyringStore) Get(profile, key string) (string, error) {
+	out, err := runSecretTool("", append([]string{"lookup"}, keyringAttributes(profile, key)...)...)
+	if errors.Is(err, errSecretNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(out, "\n"), nil
+}
+
+func (s keyringStore) Set(profile, key, value string) error {
+	if dryRunLocal("store %s of profile %s in the keyring", key, profile) {
+		return nil
+	}
+	args := append([]string{"store", "--label", fmt.Sprintf("%s %s %s", keyringService, profile, key)},
+		keyringAttributes(profile, key)...)
+	// the secret is passed on the standard input, not in the arguments
+	_, err := runSecretTool(value, args...)
+	return err
+}
+
+func (s keyringStore) Delete(profile, key string) (bool, error) {
+	value, err := s.Get(profile, key)
+	if err != nil || value == "" {
+		return false, err
+	}
+	if dryRunLocal("remove %s of profile %s from the keyring", key, profile) {
+		return true, nil
+	}
+	if _, err := runSecretTool("", append([]string{"clear"}, keyringAttributes(profile, key)...)...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func keyringAttributes(profile, key string) []string {
+	return []string{"service", keyringService, "profile", profile, "key", key}
+}
+
+// runSecretTool runs secret-tool with input on its standard input. A lookup
+// which fails without any message is a secret which is not found.
+func runSecretTool(input string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	c := exec.Command(secretTool, args...)
+	c.Stdin = strings.NewReader(input)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(args) > 0 && args[0] == "lookup" && strings.TrimSpace(stderr.String()) == "" {
+			return "", errSecretNotFound
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("keyring: %s", msg)
+		}
+		return "", fmt.Errorf("keyring: %w", err)
+	}
+	return stdout.String(), nil
+}
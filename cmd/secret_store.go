@@ -0,0 +1,198 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	// secretStoreKey selects the store of the secrets of all the profiles
+	secretStoreKey = "secret_store"
+
+	secretStorePlaintext = "plaintext"
+	secretStoreKeyring   = "keyring"
+	secretStoreFile      = "file"
+)
+
+var secretStoreNames = []string{secretStoreKeyring, secretStoreFile, secretStorePlaintext}
+
+// secretStore stores the secrets of the profiles, see secretKeys
+type secretStore interface {
+	// Name returns the value of secret_store selecting the store
+	Name() string
+	// Get returns a secret, empty when it is not set
+	Get(profile, key string) (string, error)
+	Set(profile, key, value string) error
+	// Delete removes a secret and reports whether it existed
+	Delete(profile, key string) (bool, error)
+}
+
+// plaintextStore keeps the secrets in the config file, as the other settings.
+// The changes are written when the config file is saved.
+type plaintextStore struct {
+	cf *configFile
+}
+
+func (s *plaintextStore) Name() string {
+	return secretStorePlaintext
+}
+
+func (s *plaintextStore) Get(profile, key string) (string, error) {
+	if v, ok := s.cf.Get(s.cf.ProfilePath(profile, key)); ok && v != nil {
+		return fmt.Sprint(v), nil
+	}
+	return "", nil
+}
+
+func (s *plaintextStore) Set(profile, key, value string) error {
+	s.cf.Set(s.cf.ProfilePath(profile, key), value)
+	return nil
+}
+
+func (s *plaintextStore) Delete(profile, key string) (bool, error) {
+	return s.cf.Unset(s.cf.ProfilePath(profile, key)), nil
+}
+
+// secretStoreName returns the store selected by secret_store or
+// BIZFLY_CLOUD_SECRET_STORE, the config file by default
+func secretStoreName() (string, error) {
+	name := strings.ToLower(viper.GetString(secretStoreKey))
+	if name == "" {
+		return secretStorePlaintext, nil
+	}
+	if _, ok := SliceContains(secretStoreNames, name); !ok {
+		return "", usageError("Invalid secret store %s. Valid stores: %s", name, strings.Join(secretStoreNames, ", "))
+	}
+	return name, nil
+}
+
+// newSecretStore returns the store called name, cf is the config file of the
+// plaintext store
+func newSecretStore(name string, cf *configFile) (secretStore, error) {
+	switch name {
+	case secretStorePlaintext:
+		return &plaintextStore{cf: cf}, nil
+	case secretStoreKeyring:
+		if !keyringAvailable() {
+			return nil, usageError("No keyring is available, it needs the Secret Service and secret-tool on Linux. Use the file secret store")
+		}
+		return keyringStore{}, nil
+	case secretStoreFile:
+		path, err := secretFilePath()
+		if err != nil {
+			return nil, err
+		}
+		return &fileStore{path: path}, nil
+	}
+	return nil, usageError("Invalid secret store %s. Valid stores: %s", name, strings.Join(secretStoreNames, ", "))
+}
+
+// configSecretStore returns the secret store in use, cf is the config file
+// being edited
+func configSecretStore(cf *configFile) (secretStore, error) {
+	name, err := secretStoreName()
+	if err != nil {
+		return nil, err
+	}
+	return newSecretStore(name, cf)
+}
+
+// activeStore is the store secretValue reads, so that the encrypted file is
+// decrypted once
+var activeStore secretStore
+
+// secretValue returns a secret of the active profile from the environment,
+// the config file or the secret store, in this order. The config file comes
+// first so that the secrets not migrated yet keep working.
+func secretValue(key string) (string, error) {
+	if v := configValue(key); v != "" {
+		return v, nil
+	}
+	if activeStore == nil {
+		name, err := secretStoreName()
+		if err != nil {
+			return "", err
+		}
+		if name == secretStorePlaintext {
+			return "", nil
+		}
+		if activeStore, err = newSecretStore(name, nil); err != nil {
+			return "", err
+		}
+	}
+	return activeStore.Get(getActiveProfile(), key)
+}
+
+// setProfileSecret stores a secret of a profile in the secret store and
+// removes its copy from the config file. The caller saves the config file.
+func setProfileSecret(cf *configFile, profile, key, value string) error {
+	store, err := configSecretStore(cf)
+	if err != nil {
+		return err
+	}
+	if err := store.Set(profile, key, value); err != nil {
+		return err
+	}
+	if store.Name() != secretStorePlaintext {
+		cf.Unset(cf.ProfilePath(profile, key))
+	}
+	return nil
+}
+
+// unsetProfileSecret removes a secret of a profile from the config file and
+// the secret store, and reports whether it existed. The caller saves the
+// config file.
+func unsetProfileSecret(cf *configFile, profile, key string) (bool, error) {
+	removed := cf.Unset(cf.ProfilePath(profile, key))
+	store, err := configSecretStore(cf)
+	if err != nil {
+		return removed, err
+	}
+	if store.Name() == secretStorePlaintext {
+		return removed, nil
+	}
+	deleted, err := store.Delete(profile, key)
+	return removed || deleted, err
+}
+
+// profileSecret returns a secret of a profile from the config file or the
+// secret store
+func profileSecret(cf *configFile, profile, key string) (string, error) {
+	if v, ok := cf.Get(cf.ProfilePath(profile, key)); ok && v != nil {
+		return fmt.Sprint(v), nil
+	}
+	store, err := configSecretStore(cf)
+	if err != nil {
+		return "", err
+	}
+	return store.Get(profile, key)
+}
+
+// secretStoreLocation describes where a store keeps the secrets, for the messages
+func secretStoreLocation(name string) string {
+	switch name {
+	case secretStoreKeyring:
+		return "the keyring"
+	case secretStoreFile:
+		return "the encrypted secret file"
+	}
+	return "config file"
+}
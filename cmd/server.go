@@ -18,12 +18,11 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -32,6 +31,7 @@ var (
 	serverListHeader = []string{"ID", "Name", "Zone", "Key Name", "Status", "Flavor", "Category",
 		"LAN IP", "WAN IP", "Attached Volumes", "Created At"}
 	serverTypeListHeader = []string{"ID", "Name", "Enabled", "Compute class"}
+	taskHeader           = []string{"Task ID"}
 
 	serverName string
 	// serverOS gobizfly type
@@ -73,7 +73,7 @@ var serverCmd = &cobra.Command{
 	Short: "Bizfly Cloud Server Interaction",
 	Long:  `Bizfly Cloud Server Action: Create, List, Delete, Resize, Change Type Server`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("server called")
+		logging.Debugf("server called")
 	},
 }
 
@@ -87,20 +87,49 @@ Example: bizfly server delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 You can delete multiple server with list of server id
 Example: bizfly server delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58 f5869e9c-9ab2-11ea-b9e3-e353a4f04836
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
-		for _, serverID := range args {
-			fmt.Printf("Deleting server %s \n", serverID)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		var notFound, deleted []string
+		var servers []*gobizfly.Server
+		var details []string
+		for _, arg := range args {
+			serverID, err := resolveServer(ctx, client, arg)
+			if isNotFound(err) {
+				notFound = append(notFound, arg)
+				continue
+			} else if err != nil {
+				return err
+			}
 			server, err := client.CloudServer.Get(ctx, serverID)
 			if err != nil {
 				if errors.Is(err, gobizfly.ErrNotFound) {
-					fmt.Printf("Server %s is not found", serverID)
+					notFound = append(notFound, serverID)
 					continue
 				} else {
-					fmt.Printf("Error when get server info: %v", err)
-					return
+					return fmt.Errorf("Error when get server info: %w", err)
+				}
+			}
+			servers = append(servers, server)
+			details = append(details, fmt.Sprintf("Server %s (%s)", server.Name, server.ID))
+			for _, v := range server.AttachedVolumes {
+				if v.AttachedType == attachTypeRootDisk && deleteRootDisk {
+					details = append(details, fmt.Sprintf("  root disk %s is deleted", v.ID))
+				} else {
+					details = append(details, fmt.Sprintf("  volume %s is detached", v.ID))
 				}
 			}
+		}
+		if len(servers) > 0 {
+			if err := confirmDestroy(fmt.Sprintf("%d server(s) will be deleted:", len(servers)), details); err != nil {
+				return err
+			}
+		}
+		for _, server := range servers {
+			serverID := server.ID
+			logging.Infof("Deleting server %s", serverID)
 			var deleteVolumes []string
 			if deleteRootDisk {
 				for _, v := range server.AttachedVolumes {
@@ -112,15 +141,25 @@ Example: bizfly server delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58 f5869e9c-9ab2
 			task, err := client.CloudServer.Delete(ctx, serverID, deleteVolumes)
 			if err != nil {
 				if errors.Is(err, gobizfly.ErrNotFound) {
-					fmt.Printf("Server %s is not found", serverID)
+					notFound = append(notFound, serverID)
 					continue
 				} else {
-					fmt.Printf("Error when delete server %v", err)
-					return
+					return fmt.Errorf("Error when delete server %w", err)
 				}
 			}
-			fmt.Printf("Deleting server with task id: %s\n", task.TaskID)
+			logging.Infof("Deleting server with task id: %s", task.TaskID)
+			deleted = append(deleted, serverID)
+		}
+		for _, serverID := range deleted {
+			if err := waitForDeletion(ctx, "Server "+serverID, serverStatus(client, serverID)); err != nil {
+				return err
+			}
 		}
+		if len(notFound) > 0 {
+			// the other servers are deleted, still report the missing ones
+			return notFoundError("Server %s is not found", strings.Join(notFound, ", "))
+		}
+		return nil
 	},
 }
 
@@ -128,15 +167,44 @@ Example: bizfly server delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58 f5869e9c-9ab2
 var serverListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all server in your account",
-	Long:  `List all server in your account`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	// the attached volumes are only listed with -o wide
+	Annotations: map[string]string{wideAnnotation: "true"},
+	Long: `List the servers in your account, optionally filtered, sorted and limited.
+The filters taking a list match any of the values, the different filters all have to match.
+Use: bizfly server list [--status <status>] [--zone <zone>] [--flavor <flavor>] [--category <category>] [--name <pattern>] [--vpc <vpc>] [--tag <key[=value]>] [--sort-by <column>] [--limit <n>]
+Example: bizfly server list --status ACTIVE --zone HN1 --sort-by created-at
+Example: bizfly server list --name 'web-*' --limit 5
+Example: bizfly server list --name '/^db-[0-9]+$/' --tag env=prod`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if serverListLimit < 0 {
+			return usageError("Invalid --limit %d, it must be 0 or more", serverListLimit)
+		}
+		sortColumn := -1
+		if serverListSortBy != "" {
+			column, err := columnIndex(serverListHeader, serverListSortBy)
+			if err != nil {
+				return err
+			}
+			sortColumn = column
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		filter, err := newServerFilter(ctx, client)
+		if err != nil {
+			return err
+		}
 		servers, err := client.CloudServer.List(ctx, &gobizfly.ServerListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		var data [][]string
+		var matched []*gobizfly.Server
+		var rows [][]string
 		for _, server := range servers {
+			if !filter.match(server) {
+				continue
+			}
 			var LanIP []string
 			for _, lan := range server.IPAddresses.LanAddresses {
 				LanIP = append(LanIP, lan.Address)
@@ -150,10 +218,41 @@ var serverListCmd = &cobra.Command{
 				WanIP = append(WanIP, wanv6.Address)
 			}
 			WanIPAddrs := strings.Join(WanIP, ", ")
-			data = append(data, []string{server.ID, server.Name, server.AvailabilityZone, server.KeyName, server.Status, server.FlavorName, server.Category, LanIPAddrs, WanIPAddrs, server.CreatedAt})
+			VolumeIds := []string{}
+			for _, volume := range server.AttachedVolumes {
+				VolumeIds = append(VolumeIds, volume.ID)
+			}
+			matched = append(matched, server)
+			rows = append(rows, []string{server.ID, server.Name, server.AvailabilityZone, server.KeyName, server.Status,
+				server.FlavorName, server.Category, LanIPAddrs, WanIPAddrs, strings.Join(VolumeIds, ", "), server.CreatedAt})
+		}
+		order := make([]int, len(rows))
+		for i := range order {
+			order[i] = i
+		}
+		if sortColumn >= 0 {
+			order = sortedRowOrder(rows, sortColumn)
+		}
+		if serverListLimit > 0 && len(order) > serverListLimit {
+			order = order[:serverListLimit]
+		}
+		volumesColumn := len(serverListHeader) - 2
+		listServerListHeader := serverListHeader
+		if !formatter.IsWide() {
+			// attached volumes are only shown in wide output
+			listServerListHeader = append(append([]string{}, serverListHeader[:volumesColumn]...), serverListHeader[volumesColumn+1:]...)
+		}
+		listed := make([]*gobizfly.Server, 0, len(order))
+		data := make([][]string, 0, len(order))
+		for _, i := range order {
+			row := rows[i]
+			if !formatter.IsWide() {
+				row = append(append([]string{}, row[:volumesColumn]...), row[volumesColumn+1:]...)
+			}
+			listed = append(listed, matched[i])
+			data = append(data, row)
 		}
-		listServerListHeader := append(serverListHeader[:len(serverListHeader)-2], serverListHeader[len(serverListHeader)-1])
-		formatter.Output(listServerListHeader, data)
+		return formatter.Output(listServerListHeader, data, listed)
 	},
 }
 
@@ -164,19 +263,28 @@ var serverGetCmd = &cobra.Command{
 	Long: `Get detail a server with server ID as input
 Example: bizfly server get fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return usageError("You need to specify the server. Use: bizfly server get <server>")
+		}
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 
-		server, err := client.CloudServer.Get(ctx, args[0])
+		serverID, err := resolveServer(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		server, err := client.CloudServer.Get(ctx, serverID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Server %s not found.", args[0])
-				return
+				return notFoundError("Server %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		var LanIP []string
@@ -198,7 +306,7 @@ Example: bizfly server get fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 		}
 		VolumesStr := strings.Join(VolumeIds, ", ")
 		data = append(data, []string{server.ID, server.Name, server.AvailabilityZone, server.KeyName, server.Status, server.FlavorName, server.Category, LanIPAddrs, WanIPAddrs, VolumesStr, server.CreatedAt})
-		formatter.Output(serverListHeader, data)
+		return formatter.Output(serverListHeader, data, server)
 	},
 }
 
@@ -206,109 +314,87 @@ Example: bizfly server get fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 var serverCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a server",
-	Long:  "Create a new server, return a task ID of the processing",
-	Run: func(cmd *cobra.Command, arg []string) {
-
-		if imageID == "" && volumeID == "" && snapshotID == "" {
-			fmt.Println("You need to specify image-id or volume-id or snapshot-id to create a new server")
-		}
-
-		var serverOS gobizfly.ServerOS
-
-		if imageID != "" {
-			serverOS.Type = "image"
-			serverOS.ID = imageID
-		}
-		if volumeID != "" {
-			serverOS.Type = "volume"
-			serverOS.ID = volumeID
+	Long: `Create new servers, return the task IDs of the processing.
+The server is described by the flags or by a Server manifest with --filename, the flags override the manifest.
+With --count, the name is a template rendered with {{.Index}}, from 1, and {{.Count}}.
+Use: bizfly server create --name <name> --flavor <flavor> --image-id <image-id> --rootdisk-size <size> [--user-data @<file>] [--count <n>]
+Use: bizfly server create -f server.yaml [--count <n>]
+Example: bizfly server create --name 'web-{{.Index}}' --count 3 --flavor nix.2c_4g --image-id <image-id> --rootdisk-size 40 --user-data @cloud-init.yaml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := ""
+		spec := &serverManifestSpec{}
+		if serverSpecFile != "" {
+			var err error
+			if name, spec, err = readServerSpec(serverSpecFile); err != nil {
+				return err
+			}
 		}
-
-		if snapshotID != "" {
-			serverOS.Type = "snapshot"
-			serverOS.ID = snapshotID
+		if cmd.Flags().Changed("name") {
+			name = serverName
 		}
-		rootDisk := gobizfly.ServerDisk{
-			Size: rootDiskSize,
+		applyServerCreateFlags(cmd, spec)
+		if cmd.Flags().Changed("user-data") {
+			if userData == "@-" && serverSpecFile == "-" {
+				return usageError("The manifest and the user data cannot both be read from the standard input")
+			}
+			data, err := readUserData(userData)
+			if err != nil {
+				return err
+			}
+			spec.UserData = data
 		}
-		if rootDiskVolumeType != "" {
-			rootDisk.VolumeType = &rootDiskVolumeType
-		} else {
-			rootDisk.Type = &rootDiskType
+		if name == "" {
+			return usageError("You need to specify the name of the server with --name or metadata.name of --filename")
 		}
-
-		scr := gobizfly.ServerCreateRequest{
-			Name:              serverName,
-			FlavorName:        flavorName,
-			SSHKey:            sshKey,
-			RootDisk:          &rootDisk,
-			Type:              serverCategory,
-			AvailabilityZone:  availabilityZone,
-			OS:                &serverOS,
-			NetworkPlan:       networkPlan,
-			Firewalls:         firewalls,
-			NetworkInterfaces: networkInterfaces,
-			BillingPlan:       billingPlan,
-			IsCreatedWan:      &isCreatedWan,
+		if serverCount < 1 {
+			return usageError("Invalid --count %d, it must be 1 or more", serverCount)
 		}
-		client, ctx := getApiClient(cmd)
-		svrTask, err := client.CloudServer.Create(ctx, &scr)
+		names, err := serverNames(name, serverCount)
 		if err != nil {
-			fmt.Printf("Create server error: %v", err)
-			os.Exit(1)
+			return err
 		}
-
-		fmt.Printf("Creating server with task id: %v\n", svrTask.Task[0])
-	},
-}
-
-// serverRebootCmd represents the reboot server command
-var serverRebootCmd = &cobra.Command{
-	Use:   "reboot",
-	Short: "Reboot a server. This is soft reboot",
-	Long: `
-Reboot a server
-Use: bizfly server reboot <server-id>
-`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) < 1 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server reboot <server-id>")
-			os.Exit(1)
+		regionName := activeRegionName(cmd)
+		if spec.AvailabilityZone == "" {
+			spec.AvailabilityZone = defaultAvailabilityZone(regionName)
+		}
+		if err := validateServerSpec(spec, regionName); err != nil {
+			return err
 		}
-		serverID := args[0]
-		client, ctx := getApiClient(cmd)
-		res, err := client.CloudServer.SoftReboot(ctx, serverID)
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			fmt.Printf("Reboot server error %v", err)
-			os.Exit(1)
+			return err
 		}
-		fmt.Println(res.Message)
-
-	},
-}
-
-// serverHardRebootCmd represents the hard reboot server command
-var serverHardRebootCmd = &cobra.Command{
-	Use:   "hard reboot",
-	Short: "Hard reboot a server",
-	Long: `
-Hard reboot a server.
-Use: bizfly server hard reboot <server-id>
-`,
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) < 2 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server hard reboot <server-id>")
-			os.Exit(1)
+		var data [][]string
+		var tasks []interface{}
+		var taskIDs []string
+		for _, instance := range names {
+			scr, err := serverCreateRequest(ctx, client, instance, spec)
+			if err != nil {
+				return err
+			}
+			svrTask, err := client.CloudServer.Create(ctx, scr)
+			if err != nil {
+				if len(taskIDs) > 0 {
+					logging.Warnf("Created %d of %d servers, tasks: %s", len(taskIDs), len(names), strings.Join(taskIDs, ", "))
+				}
+				return fmt.Errorf("Create server %s error: %w", instance, err)
+			}
+			for _, taskID := range svrTask.Task {
+				data = append(data, []string{taskID})
+				taskIDs = append(taskIDs, taskID)
+			}
+			tasks = append(tasks, svrTask)
 		}
-		serverID := args[1]
-		client, ctx := getApiClient(cmd)
-		res, err := client.CloudServer.HardReboot(ctx, serverID)
-		if err != nil {
-			fmt.Printf("Hard Reboot server error %v\n", err)
-			os.Exit(1)
+		// a list even for one server, so that scripts do not depend on --count
+		if err := formatter.Output(taskHeader, data, tasks); err != nil {
+			return err
 		}
-		fmt.Println(res.Message)
-
+		for _, taskID := range taskIDs {
+			if err := waitForTask(ctx, client, taskID); err != nil {
+				return err
+			}
+		}
+		return nil
 	},
 }
 
@@ -320,20 +406,24 @@ var serverStopCmd = &cobra.Command{
 Stop a server.
 Use: bizfly server stop <server-id>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server stop <server-id>")
-			os.Exit(1)
+			return usageError("You need to specify server-id in the command. Use bizfly server stop <server-id>")
 		}
-		serverID := args[0]
-		client, ctx := getApiClient(cmd)
-		_, err := client.CloudServer.Stop(ctx, serverID)
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			fmt.Printf("Stop server error %v\n", err)
-			os.Exit(1)
+			return err
 		}
-		fmt.Printf("Stopping server: %s\n", serverID)
-
+		serverID, err := resolveServer(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		_, err = client.CloudServer.Stop(ctx, serverID)
+		if err != nil {
+			return fmt.Errorf("Stop server error %w", err)
+		}
+		logging.Infof("Stopping server: %s", serverID)
+		return waitForStatus(ctx, "Server "+serverID, serverStatus(client, serverID), serverStoppedStatus...)
 	},
 }
 
@@ -345,20 +435,24 @@ var serverStartCmd = &cobra.Command{
 Start a server.
 Use: bizfly server start <server-id>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server start <server-id>")
-			os.Exit(1)
+			return usageError("You need to specify server-id in the command. Use bizfly server start <server-id>")
 		}
-		serverID := args[0]
-		client, ctx := getApiClient(cmd)
-		_, err := client.CloudServer.Start(ctx, serverID)
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			fmt.Printf("Start server error %v\n", err)
-			os.Exit(1)
+			return err
 		}
-		fmt.Printf("Starting server: %s\n", serverID)
-
+		serverID, err := resolveServer(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		_, err = client.CloudServer.Start(ctx, serverID)
+		if err != nil {
+			return fmt.Errorf("Start server error %w", err)
+		}
+		logging.Infof("Starting server: %s", serverID)
+		return waitForStatus(ctx, "Server "+serverID, serverStatus(client, serverID), serverActiveStatus...)
 	},
 }
 
@@ -370,20 +464,24 @@ var serverResizeCmd = &cobra.Command{
 Resize a server.
 Use: bizfly server resize <server-id> --flavor <flavor name>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server resize <server-id> --flavor")
-			os.Exit(1)
+			return usageError("You need to specify server-id in the command. Use bizfly server resize <server-id> --flavor")
 		}
-		serverID := args[0]
-		client, ctx := getApiClient(cmd)
-		_, err := client.CloudServer.Resize(ctx, serverID, flavorName)
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			fmt.Printf("Resize server error %v\n", err)
-			os.Exit(1)
+			return err
 		}
-		fmt.Printf("Resizing server: %s\n", serverID)
-
+		serverID, err := resolveServer(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		task, err := client.CloudServer.Resize(ctx, serverID, flavorName)
+		if err != nil {
+			return fmt.Errorf("Resize server error %w", err)
+		}
+		logging.Infof("Resizing server %s with task id: %s", serverID, task.TaskID)
+		return waitForTask(ctx, client, task.TaskID)
 	},
 }
 
@@ -392,19 +490,28 @@ var serverAddVPCCmd = &cobra.Command{
 	Short: "Add VPC to Server",
 	Long: "Add VPC to Server.\nUse: bizfly server add_vpc <server-id> --vpc-ids <vpc_ids>\n" +
 		"Example: /bizfly server add-vpc {server-id} --vpc-ids {vpc-id1} --vpc-ids {vpc-id2}\n",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server add_vpc <server-id> --vpc-ids")
-			os.Exit(1)
+			return usageError("You need to specify server-id in the command. Use bizfly server add_vpc <server-id> --vpc-ids")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		serverID := args[0]
-		client, ctx := getApiClient(cmd)
-		_, err := client.CloudServer.AddVirtualPrivateNetwork(ctx, serverID, vpcIDs)
+		serverID, err := resolveServer(ctx, client, args[0])
 		if err != nil {
-			fmt.Printf("Add VPC to server error %v\n", err)
-			os.Exit(1)
+			return err
 		}
-		fmt.Printf("Adding VPC to server: %s\n", serverID)
+		vpcIDs, err := resolveAll(ctx, client, vpcIDs, resolveVPC)
+		if err != nil {
+			return err
+		}
+		_, err = client.CloudServer.AddVirtualPrivateNetwork(ctx, serverID, vpcIDs)
+		if err != nil {
+			return fmt.Errorf("Add VPC to server error %w", err)
+		}
+		logging.Infof("Adding VPC to server: %s", serverID)
+		return nil
 	},
 }
 
@@ -413,19 +520,28 @@ var serverRemoveVPCCmd = &cobra.Command{
 	Short: "Remove VPC to Server",
 	Long: "Remove VPC to Server.\nUse: bizfly server remove_vpc <server-id> --vpc-ids <vpc_ids>\n" +
 		"Example: /bizfly server remove-vpc {server-id} --vpc-ids {vpc-id1} --vpc-ids {vpc-id2}\n",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server remove_vpc <server-id> --vpc-ids")
-			os.Exit(1)
+			return usageError("You need to specify server-id in the command. Use bizfly server remove_vpc <server-id> --vpc-ids")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		serverID := args[0]
-		client, ctx := getApiClient(cmd)
-		_, err := client.CloudServer.RemoveNetworkInterface(ctx, serverID, vpcIDs)
+		serverID, err := resolveServer(ctx, client, args[0])
 		if err != nil {
-			fmt.Printf("Remove VPC to server error %v\n", err)
-			os.Exit(1)
+			return err
 		}
-		fmt.Printf("Removing VPC to server: %s\n", serverID)
+		vpcIDs, err := resolveAll(ctx, client, vpcIDs, resolveVPC)
+		if err != nil {
+			return err
+		}
+		_, err = client.CloudServer.RemoveNetworkInterface(ctx, serverID, vpcIDs)
+		if err != nil {
+			return fmt.Errorf("Remove VPC to server error %w", err)
+		}
+		logging.Infof("Removing VPC to server: %s", serverID)
+		return nil
 	},
 }
 
@@ -436,19 +552,21 @@ var serverListTypes = &cobra.Command{
 List server types.
 Use: bizfly server list-types
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		resp, err := client.CloudServer.ListServerTypes(ctx)
 		if err != nil {
-			fmt.Printf("List server types error %v\n", err)
-			os.Exit(1)
+			return fmt.Errorf("List server types error %w", err)
 		}
 		var data [][]string
 		for _, serverType := range resp {
 			data = append(data, []string{serverType.ID, serverType.Name, strconv.FormatBool(serverType.Enabled),
 				strings.Join(serverType.ComputeClass, ",")})
 		}
-		formatter.Output(serverTypeListHeader, data)
+		return formatter.Output(serverTypeListHeader, data, resp)
 	},
 }
 
@@ -459,19 +577,24 @@ var serverChangeNetworkPlanCmd = &cobra.Command{
 Change network plan.
 Use: bizfly server change-network-plan <server-id> --network-plan <network-plan>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server change-network-plan <server-id> --network-plan")
-			os.Exit(1)
+			return usageError("You need to specify server-id in the command. Use bizfly server change-network-plan <server-id> --network-plan")
 		}
-		serverID := args[0]
-		client, ctx := getApiClient(cmd)
-		err := client.CloudServer.ChangeNetworkPlan(ctx, serverID, networkPlan)
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			fmt.Printf("Change network plan error %v\n", err)
-			os.Exit(1)
+			return err
 		}
-		fmt.Printf("Changing network plan of server %s to %s\n", serverID, networkPlan)
+		serverID, err := resolveServer(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		err = client.CloudServer.ChangeNetworkPlan(ctx, serverID, networkPlan)
+		if err != nil {
+			return fmt.Errorf("Change network plan error %w", err)
+		}
+		logging.Infof("Changing network plan of server %s to %s", serverID, networkPlan)
+		return nil
 	},
 }
 
@@ -482,19 +605,24 @@ var serverSwitchBillingPlanCmd = &cobra.Command{
 Switch billing plan.
 Use: bizfly server switch-billing-plan <server-id> --billing-plan <billing-plan>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server switch-billing-plan <server-id> --billing-plan")
-			os.Exit(1)
+			return usageError("You need to specify server-id in the command. Use bizfly server switch-billing-plan <server-id> --billing-plan")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		serverID := args[0]
-		client, ctx := getApiClient(cmd)
-		err := client.CloudServer.SwitchBillingPlan(ctx, serverID, billingPlan)
+		serverID, err := resolveServer(ctx, client, args[0])
 		if err != nil {
-			fmt.Printf("Switch billing plan error %v\n", err)
-			os.Exit(1)
+			return err
 		}
-		fmt.Printf("Switching billing plan of server: %s to %s\n", serverID, billingPlan)
+		err = client.CloudServer.SwitchBillingPlan(ctx, serverID, billingPlan)
+		if err != nil {
+			return fmt.Errorf("Switch billing plan error %w", err)
+		}
+		logging.Infof("Switching billing plan of server: %s to %s", serverID, billingPlan)
+		return nil
 	},
 }
 
@@ -505,32 +633,51 @@ var serverRename = &cobra.Command{
 Rename server.
 Use: bizfly server rename <server-id> --name <name>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify server-id in the command. Use bizfly server rename <server-id> --name")
-			os.Exit(1)
+			return usageError("You need to specify server-id in the command. Use bizfly server rename <server-id> --name")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		serverID, err := resolveServer(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		serverID := args[0]
-		client, ctx := getApiClient(cmd)
-		err := client.CloudServer.Rename(ctx, serverID, serverName)
+		err = client.CloudServer.Rename(ctx, serverID, serverName)
 		if err != nil {
-			fmt.Printf("Rename server error %v ", err)
-			os.Exit(1)
+			return fmt.Errorf("Rename server error %w", err)
 		}
-		fmt.Printf("Renaming server: %s to %s ", serverID, serverName)
+		logging.Infof("Renaming server: %s to %s", serverID, serverName)
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(serverCmd)
+	slf := serverListCmd.Flags()
+	slf.StringSliceVar(&serverListStatuses, "status", nil, "Only list the servers with one of these statuses, e.g. ACTIVE,SHUTOFF")
+	slf.StringSliceVar(&serverListZones, "zone", nil, "Only list the servers in one of these availability zones, e.g. HN1")
+	slf.StringSliceVar(&serverListFlavors, "flavor", nil, "Only list the servers with one of these flavors")
+	slf.StringSliceVar(&serverListCategories, "category", nil, "Only list the servers of one of these categories: basic, premium, dedicated, vps or enterprise")
+	slf.StringVar(&serverListName, "name", "", "Only list the servers whose name matches a glob, e.g. 'web-*', or a regular expression between slashes, e.g. '/^web-[0-9]+$/'")
+	slf.StringVar(&serverListVPC, "vpc", "", "Only list the servers with an address in a VPC, by ID or name")
+	slf.StringArrayVar(&serverListTags, "tag", nil, "Only list the servers with a metadata key or key=value, repeat for several tags")
+	slf.StringVar(&serverListSortBy, "sort-by", "", "Sort the servers by a column, e.g. name or created-at")
+	slf.IntVar(&serverListLimit, "limit", 0, "List at most this number of servers, 0 for all")
 	serverCmd.AddCommand(serverListCmd)
 	serverCmd.AddCommand(serverGetCmd)
 	serverDeleteCmd.PersistentFlags().BoolVar(&deleteRootDisk, "delete-rootdisk", true, "Delete rootdisk of a server")
+	addConfirmFlag(serverDeleteCmd)
+	addWaitFlags(serverDeleteCmd)
 	serverCmd.AddCommand(serverDeleteCmd)
 
 	scpf := serverCreateCmd.PersistentFlags()
-	scpf.StringVar(&serverName, "name", "", "Name of server")
-	_ = cobra.MarkFlagRequired(scpf, "name")
+	scpf.StringVarP(&serverSpecFile, "filename", "f", "", "Server manifest file, - for the standard input")
+	scpf.StringVar(&serverName, "name", "", "Name of server, a template with --count, e.g. web-{{.Index}}")
+	scpf.StringVar(&userData, "user-data", "", "User data, e.g. a cloud-init config. Use @<file> to read a file, @- for the standard input")
+	scpf.IntVar(&serverCount, "count", 1, "Number of servers to create")
 	scpf.StringVar(&imageID, "image-id", "", "ID of OS image. Create a root disk using this image ID")
 	scpf.StringVar(&volumeID, "volume-id", "", "ID of volume. Create a server using an existing root disk volume.")
 	scpf.StringVar(&snapshotID, "snapshot-id", "", "ID of snapshot. Create a server from a snapshot ID.")
@@ -538,26 +685,28 @@ func init() {
 	scpf.StringVar(&networkPlan, "network-plan", "", "Network plan of server (free_bandwidth|free_datatransfer)")
 	scpf.StringArrayVar(&networkInterfaces, "net-interface", []string{}, "Network interface IDs")
 	scpf.StringArrayVar(&firewalls, "firewall", []string{}, "Firewalls IDs")
-	_ = cobra.MarkFlagRequired(scpf, "flavor")
 	scpf.StringVar(&serverCategory, "category", "premium", "Server category: basic, premium, dedicated, vps or enterprise.")
-	scpf.StringVar(&availabilityZone, "availability-zone", "HN1", "Availability Zone of server.")
+	scpf.StringVar(&availabilityZone, "availability-zone", "", "Availability Zone of server, default: the first zone of the region, e.g. HN1")
 	scpf.StringVar(&rootDiskType, "rootdisk-type", "HDD", "Type of root disk: HDD or SSD.")
 	scpf.StringVar(&rootDiskVolumeType, "rootdisk-volume-type", "", "Type of root disk volume - get from listing volume types: PREMIUM-HDD1")
 	scpf.IntVar(&rootDiskSize, "rootdisk-size", 0, "Size of root disk in Gigabyte. Minimum is 20GB")
-	_ = cobra.MarkFlagRequired(scpf, "rootdisk-size")
 	scpf.StringVar(&sshKey, "ssh-key", "", "SSH key")
 	scpf.BoolVar(&isCreatedWan, "is-created-wan-ip", true, "Choose whatever create a WAN IP for server")
 	scpf.StringVar(&billingPlan, "billing-plan", "saving_plan", "Billing plan of server (saving_plan|on_demand|spot_instance)."+
 		" Default is saving_plan")
 
+	addWaitFlags(serverCreateCmd)
 	serverCmd.AddCommand(serverCreateCmd)
 	serverCmd.AddCommand(serverRebootCmd)
 	serverCmd.AddCommand(serverHardRebootCmd)
+	addWaitFlags(serverStopCmd)
 	serverCmd.AddCommand(serverStopCmd)
+	addWaitFlags(serverStartCmd)
 	serverCmd.AddCommand(serverStartCmd)
 
 	serverResizeCmd.PersistentFlags().StringVar(&flavorName, "flavor", "", "Name of flavor.")
 	_ = cobra.MarkFlagRequired(serverResizeCmd.PersistentFlags(), "flavor")
+	addWaitFlags(serverResizeCmd)
 	serverCmd.AddCommand(serverResizeCmd)
 
 	serverAddVPCCmd.PersistentFlags().StringArrayVar(&vpcIDs, "vpc-ids", []string{}, "The VPC IDs")
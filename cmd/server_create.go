@@ -0,0 +1,325 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/bizflycloud/bizflyctl/constants"
+	"github.com/bizflycloud/gobizfly"
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	minRootDiskSize = 20
+	// maxUserDataSize is the limit of the API on the base64 encoded user data
+	maxUserDataSize = 65535
+)
+
+var (
+	serverSpecFile string
+	userData       string
+	serverCount    int
+
+	serverCategories = []string{"basic", "premium", "dedicated", "vps", "enterprise"}
+	// flavorPattern matches the flavor names, e.g. nix.2c_4g, see bizfly flavor list
+	flavorPattern = regexp.MustCompile(`\d+c_\d+g`)
+)
+
+// serverNameData is the data of the name template of server create
+type serverNameData struct {
+	// Index is the number of the server, from 1
+	Index int
+	Count int
+}
+
+// readServerSpec reads the Server manifest of server create -f, "-" is the
+// standard input
+func readServerSpec(path string) (string, *serverManifestSpec, error) {
+	var resources []*manifestResource
+	var err error
+	if path == "-" {
+		resources, err = decodeManifest("stdin", os.Stdin)
+	} else {
+		f, openErr := os.Open(path)
+		if openErr != nil {
+			return "", nil, usageError("Read server spec error: %v", openErr)
+		}
+		resources, err = decodeManifest(path, f)
+		_ = f.Close()
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if len(resources) != 1 || resources[0].Kind != "Server" {
+		return "", nil, usageError("%s must contain exactly one resource of kind Server", path)
+	}
+	return resources[0].Metadata.Name, resources[0].spec.(*serverManifestSpec), nil
+}
+
+// applyServerCreateFlags sets the fields of spec of the flags which are set,
+// so that the flags override the spec file
+func applyServerCreateFlags(cmd *cobra.Command, spec *serverManifestSpec) {
+	changed := func(name string) bool { return cmd.Flags().Changed(name) }
+	if changed("flavor") {
+		spec.Flavor = flavorName
+	}
+	if changed("category") {
+		spec.Category = serverCategory
+	}
+	if changed("availability-zone") {
+		spec.AvailabilityZone = availabilityZone
+	}
+	if changed("image-id") {
+		spec.Image, spec.Volume, spec.Snapshot = imageID, "", ""
+	}
+	if changed("volume-id") {
+		spec.Image, spec.Volume, spec.Snapshot = "", volumeID, ""
+	}
+	if changed("snapshot-id") {
+		spec.Image, spec.Volume, spec.Snapshot = "", "", snapshotID
+	}
+	if changed("rootdisk-size") || changed("rootdisk-type") || changed("rootdisk-volume-type") {
+		if spec.RootDisk == nil {
+			spec.RootDisk = &serverManifestDisk{}
+		}
+		if changed("rootdisk-size") {
+			spec.RootDisk.Size = rootDiskSize
+		}
+		if changed("rootdisk-type") {
+			spec.RootDisk.Type, spec.RootDisk.VolumeType = rootDiskType, ""
+		}
+		if changed("rootdisk-volume-type") {
+			spec.RootDisk.Type, spec.RootDisk.VolumeType = "", rootDiskVolumeType
+		}
+	}
+	if changed("ssh-key") {
+		spec.SSHKey = sshKey
+	}
+	if changed("firewall") {
+		spec.Firewalls = firewalls
+	}
+	if changed("net-interface") {
+		spec.NetworkInterfaces = networkInterfaces
+	}
+	if changed("network-plan") {
+		spec.NetworkPlan = networkPlan
+	}
+	if changed("billing-plan") {
+		spec.BillingPlan = billingPlan
+	}
+	if changed("is-created-wan-ip") {
+		spec.WanIP = &isCreatedWan
+	}
+}
+
+// readUserData returns the user data of --user-data, a file with @<file>,
+// the standard input with @- or the value itself
+func readUserData(value string) (string, error) {
+	if !strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+	var b []byte
+	var err error
+	if value == "@-" {
+		b, err = io.ReadAll(os.Stdin)
+	} else {
+		b, err = os.ReadFile(value[1:])
+	}
+	if err != nil {
+		return "", usageError("Read user data error: %v", err)
+	}
+	return string(b), nil
+}
+
+// validateServerSpec checks a server before it is sent to the API, so that
+// the mistakes are reported at once and not by a failed task
+func validateServerSpec(spec *serverManifestSpec, regionName string) error {
+	sources := 0
+	for _, source := range []string{spec.Image, spec.Volume, spec.Snapshot} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return usageError("You need to specify exactly one of image-id, volume-id and snapshot-id to create a new server")
+	}
+	if spec.Flavor == "" {
+		return usageError("You need to specify the flavor. Use: bizfly flavor list")
+	}
+	if !flavorPattern.MatchString(spec.Flavor) {
+		return usageError("Invalid flavor %s, e.g. nix.2c_4g. Use: bizfly flavor list", spec.Flavor)
+	}
+	if spec.Category != "" {
+		if _, ok := SliceContains(serverCategories, spec.Category); !ok {
+			return usageError("Invalid category %s. Valid categories: %s", spec.Category, strings.Join(serverCategories, ", "))
+		}
+	}
+	if spec.Volume == "" {
+		// a server created from a volume boots on it, it has no new root disk
+		if spec.RootDisk == nil || spec.RootDisk.Size == 0 {
+			return usageError("You need to specify the root disk size, at least %dGB", minRootDiskSize)
+		}
+		if spec.RootDisk.Size < minRootDiskSize {
+			return usageError("Invalid root disk size %dGB, it must be at least %dGB", spec.RootDisk.Size, minRootDiskSize)
+		}
+	}
+	if err := validateAvailabilityZone(spec.AvailabilityZone, regionName); err != nil {
+		return err
+	}
+	return validateUserData(spec.UserData)
+}
+
+// defaultAvailabilityZone returns the first availability zone of a region, or
+// an empty zone for the API to choose when the zones of the region are unknown
+func defaultAvailabilityZone(regionName string) string {
+	if zones := constants.AvailabilityZones[regionName]; len(zones) > 0 {
+		return zones[0]
+	}
+	return ""
+}
+
+// validateAvailabilityZone checks that a zone belongs to a region whose zones
+// are known
+func validateAvailabilityZone(zone, regionName string) error {
+	if zone == "" {
+		return nil
+	}
+	if zones, ok := constants.AvailabilityZones[regionName]; ok {
+		if _, ok := SliceContains(zones, zone); !ok {
+			return usageError("Invalid availability zone %s for region %s. Valid zones: %s", zone,
+				regionName, strings.Join(zones, ", "))
+		}
+	}
+	return nil
+}
+
+// validateUserData checks the size of the user data, and the YAML syntax of a
+// cloud-config
+func validateUserData(data string) error {
+	if data == "" {
+		return nil
+	}
+	if size := base64.StdEncoding.EncodedLen(len(data)); size > maxUserDataSize {
+		return usageError("The user data is too large: %d bytes encoded, the limit is %d", size, maxUserDataSize)
+	}
+	if strings.HasPrefix(data, "#cloud-config") {
+		var config map[string]interface{}
+		if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+			return usageError("Invalid cloud-config user data: %v", err)
+		}
+	}
+	return nil
+}
+
+// serverNames renders the name template for each of count servers. The names
+// have to be different when several servers are created.
+func serverNames(name string, count int) ([]string, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(name)
+	if err != nil {
+		return nil, usageError("Invalid server name %q: %v", name, err)
+	}
+	names := make([]string, 0, count)
+	seen := make(map[string]bool, count)
+	for i := 1; i <= count; i++ {
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, serverNameData{Index: i, Count: count}); err != nil {
+			return nil, usageError("Invalid server name %q: %v", name, err)
+		}
+		rendered := strings.TrimSpace(b.String())
+		if rendered == "" {
+			return nil, usageError("The server name %q is empty", name)
+		}
+		if seen[rendered] {
+			return nil, usageError("The servers would all be named %s, use {{.Index}} in the name, e.g. web-{{.Index}}", rendered)
+		}
+		seen[rendered] = true
+		names = append(names, rendered)
+	}
+	return names, nil
+}
+
+// serverCreateRequest returns the request creating a server of a spec, with
+// the defaults of server create
+func serverCreateRequest(ctx context.Context, client *gobizfly.Client, name string, spec *serverManifestSpec) (*gobizfly.ServerCreateRequest, error) {
+	serverOS := gobizfly.ServerOS{Type: "image", ID: spec.Image}
+	var err error
+	switch {
+	case spec.Volume != "":
+		serverOS.Type = "volume"
+		if serverOS.ID, err = resolveVolume(ctx, client, spec.Volume); err != nil {
+			return nil, err
+		}
+	case spec.Snapshot != "":
+		serverOS = gobizfly.ServerOS{Type: "snapshot", ID: spec.Snapshot}
+	}
+	rootDisk := gobizfly.ServerDisk{}
+	if spec.RootDisk != nil {
+		rootDisk.Size = spec.RootDisk.Size
+		if spec.RootDisk.VolumeType != "" {
+			rootDisk.VolumeType = &spec.RootDisk.VolumeType
+		} else if spec.RootDisk.Type != "" {
+			rootDisk.Type = &spec.RootDisk.Type
+		}
+	}
+	if rootDisk.Type == nil && rootDisk.VolumeType == nil {
+		diskType := "HDD"
+		rootDisk.Type = &diskType
+	}
+	isCreatedWan := true
+	if spec.WanIP != nil {
+		isCreatedWan = *spec.WanIP
+	}
+	category := spec.Category
+	if category == "" {
+		category = "premium"
+	}
+	billingPlan := spec.BillingPlan
+	if billingPlan == "" {
+		billingPlan = "saving_plan"
+	}
+	scr := &gobizfly.ServerCreateRequest{
+		Name:              name,
+		FlavorName:        spec.Flavor,
+		RootDisk:          &rootDisk,
+		Type:              category,
+		AvailabilityZone:  spec.AvailabilityZone,
+		OS:                &serverOS,
+		NetworkPlan:       spec.NetworkPlan,
+		NetworkInterfaces: spec.NetworkInterfaces,
+		BillingPlan:       billingPlan,
+		IsCreatedWan:      &isCreatedWan,
+		UserData:          spec.UserData,
+	}
+	if spec.SSHKey != "" {
+		if scr.SSHKey, err = resolveSSHKey(ctx, client, spec.SSHKey); err != nil {
+			return nil, err
+		}
+	}
+	if scr.Firewalls, err = resolveAll(ctx, client, spec.Firewalls, resolveFirewall); err != nil {
+		return nil, err
+	}
+	return scr, nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServerNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		count   int
+		want    []string
+		wantErr bool
+	}{
+		{name: "web", count: 1, want: []string{"web"}},
+		{name: "web-{{.Index}}", count: 3, want: []string{"web-1", "web-2", "web-3"}},
+		{name: "db-{{.Index}}-of-{{.Count}}", count: 2, want: []string{"db-1-of-2", "db-2-of-2"}},
+		{name: "{{printf \"api-%02d\" .Index}}", count: 2, want: []string{"api-01", "api-02"}},
+		{name: "web", count: 2, wantErr: true},
+		{name: "web-{{.Index", count: 1, wantErr: true},
+		{name: "web-{{.Zone}}", count: 1, wantErr: true},
+	}
+	for _, tc := range tests {
+		got, err := serverNames(tc.name, tc.count)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("%s: got %v, want an error", tc.name, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: got %v, %v, want %v", tc.name, got, err, tc.want)
+		}
+	}
+}
+
+func TestValidateServerSpec(t *testing.T) {
+	valid := func() *serverManifestSpec {
+		return &serverManifestSpec{Flavor: "nix.2c_4g", Category: "premium", AvailabilityZone: "HN1",
+			Image: "11111111-1111-4111-8111-111111111111", RootDisk: &serverManifestDisk{Size: 40}}
+	}
+	tests := []struct {
+		name    string
+		change  func(spec *serverManifestSpec)
+		wantErr string
+	}{
+		{name: "valid", change: func(spec *serverManifestSpec) {}},
+		{name: "no source", change: func(spec *serverManifestSpec) { spec.Image = "" }, wantErr: "exactly one of"},
+		{name: "two sources", change: func(spec *serverManifestSpec) { spec.Snapshot = "snap" }, wantErr: "exactly one of"},
+		{name: "no flavor", change: func(spec *serverManifestSpec) { spec.Flavor = "" }, wantErr: "specify the flavor"},
+		{name: "invalid flavor", change: func(spec *serverManifestSpec) { spec.Flavor = "large" }, wantErr: "Invalid flavor"},
+		{name: "invalid category", change: func(spec *serverManifestSpec) { spec.Category = "gold" }, wantErr: "Invalid category"},
+		{name: "no root disk", change: func(spec *serverManifestSpec) { spec.RootDisk = nil }, wantErr: "root disk size"},
+		{name: "small root disk", change: func(spec *serverManifestSpec) { spec.RootDisk.Size = 10 }, wantErr: "at least 20GB"},
+		{name: "volume without root disk", change: func(spec *serverManifestSpec) {
+			spec.Image, spec.Volume, spec.RootDisk = "", "boot", nil
+		}},
+		{name: "zone of another region", change: func(spec *serverManifestSpec) { spec.AvailabilityZone = "HCM1" },
+			wantErr: "Invalid availability zone"},
+		{name: "cloud-config", change: func(spec *serverManifestSpec) {
+			spec.UserData = "#cloud-config\npackages:\n  - nginx\n"
+		}},
+		{name: "invalid cloud-config", change: func(spec *serverManifestSpec) {
+			spec.UserData = "#cloud-config\npackages: [nginx\n"
+		}, wantErr: "Invalid cloud-config"},
+		{name: "shell script", change: func(spec *serverManifestSpec) { spec.UserData = "#!/bin/sh\necho [\n" }},
+		{name: "large user data", change: func(spec *serverManifestSpec) {
+			spec.UserData = strings.Repeat("x", maxUserDataSize)
+		}, wantErr: "too large"},
+	}
+	for _, tc := range tests {
+		spec := valid()
+		tc.change(spec)
+		err := validateServerSpec(spec, "HaNoi")
+		switch {
+		case tc.wantErr == "" && err != nil:
+			t.Errorf("%s: %v", tc.name, err)
+		case tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)):
+			t.Errorf("%s: got %v, want %q", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestDefaultAvailabilityZone(t *testing.T) {
+	for regionName, want := range map[string]string{"HaNoi": "HN1", "HoChiMinh": "HCM1", "VC-HaNoi": ""} {
+		if got := defaultAvailabilityZone(regionName); got != want {
+			t.Errorf("%s: got %q, want %q", regionName, got, want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bizflycloud/gobizfly"
+)
+
+var (
+	serverListStatuses   []string
+	serverListZones      []string
+	serverListFlavors    []string
+	serverListCategories []string
+	serverListName       string
+	serverListVPC        string
+	serverListTags       []string
+	serverListSortBy     string
+	serverListLimit      int
+)
+
+// serverFilter selects the servers of server list. The list API of gobizfly
+// takes no filters, so they are applied to the listed servers.
+type serverFilter struct {
+	statuses   []string
+	zones      []string
+	flavors    []string
+	categories []string
+	name       func(string) bool
+	// vpcNetworks are the subnets of --vpc, a server is in the VPC when one
+	// of its LAN addresses is in a subnet
+	vpcNetworks []*net.IPNet
+	// tags are key or key=value pairs matched against the metadata
+	tags []string
+}
+
+// newServerFilter validates the filter flags and resolves the VPC of --vpc
+func newServerFilter(ctx context.Context, client *gobizfly.Client) (*serverFilter, error) {
+	f := &serverFilter{statuses: serverListStatuses, zones: serverListZones, flavors: serverListFlavors,
+		categories: serverListCategories, tags: serverListTags}
+	if serverListName != "" {
+		match, err := nameMatcher(serverListName)
+		if err != nil {
+			return nil, err
+		}
+		f.name = match
+	}
+	if serverListVPC != "" {
+		vpcID, err := resolveVPC(ctx, client, serverListVPC)
+		if err != nil {
+			return nil, err
+		}
+		vpc, err := client.CloudServer.VPCNetworks().Get(ctx, vpcID)
+		if err != nil {
+			if errors.Is(err, gobizfly.ErrNotFound) {
+				return nil, notFoundError("VPC %s is not found", serverListVPC)
+			}
+			return nil, err
+		}
+		for _, subnet := range vpc.Subnets {
+			if _, network, err := net.ParseCIDR(subnet.CIDR); err == nil {
+				f.vpcNetworks = append(f.vpcNetworks, network)
+			}
+		}
+		if len(f.vpcNetworks) == 0 {
+			return nil, usageError("VPC %s has no subnet", serverListVPC)
+		}
+	}
+	return f, nil
+}
+
+// nameMatcher returns a matcher of a glob, e.g. web-*, or of a regular
+// expression between slashes, e.g. /^web-[0-9]+$/
+func nameMatcher(pattern string) (func(string) bool, error) {
+	if len(pattern) > 1 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+		re, err := regexp.Compile(pattern[1 : len(pattern)-1])
+		if err != nil {
+			return nil, usageError("Invalid --name %s: %v", pattern, err)
+		}
+		return re.MatchString, nil
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, usageError("Invalid --name %s: %v", pattern, err)
+	}
+	return func(name string) bool {
+		ok, _ := path.Match(pattern, name)
+		return ok
+	}, nil
+}
+
+// match reports whether a server passes all the filters
+func (f *serverFilter) match(server *gobizfly.Server) bool {
+	if !matchAny(f.statuses, server.Status) || !matchAny(f.zones, server.AvailabilityZone) ||
+		!matchAny(f.flavors, server.FlavorName) || !matchAny(f.categories, server.Category) {
+		return false
+	}
+	if f.name != nil && !f.name(server.Name) {
+		return false
+	}
+	if len(f.vpcNetworks) > 0 && !f.inVPC(server) {
+		return false
+	}
+	for _, tag := range f.tags {
+		key, value, hasValue := strings.Cut(tag, "=")
+		v, ok := server.Metadata[key]
+		if !ok || (hasValue && v != value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *serverFilter) inVPC(server *gobizfly.Server) bool {
+	for _, lan := range server.IPAddresses.LanAddresses {
+		ip := net.ParseIP(lan.Address)
+		for _, network := range f.vpcNetworks {
+			if ip != nil && network.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchAny reports whether value is one of values, ignoring the case. No
+// values match everything.
+func matchAny(values []string, value string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// columnIndex returns the index of a column of header. The column is matched
+// ignoring the case, spaces, dashes and underscores, e.g. created-at for
+// "Created At".
+func columnIndex(header []string, column string) (int, error) {
+	normalize := strings.NewReplacer(" ", "", "-", "", "_", "")
+	want := strings.ToLower(normalize.Replace(column))
+	for i, name := range header {
+		if strings.ToLower(normalize.Replace(name)) == want {
+			return i, nil
+		}
+	}
+	return 0, usageError("Invalid column %s. Valid columns: %s", column, strings.Join(header, ", "))
+}
+
+// sortedRowOrder returns the order of rows sorted by a column, rows with the
+// same value keep their order
+func sortedRowOrder(rows [][]string, column int) []int {
+	order := make([]int, len(rows))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return rows[order[i]][column] < rows[order[j]][column]
+	})
+	return order
+}
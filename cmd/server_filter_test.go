@@ -0,0 +1,61 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "testing"
+
+func TestNameMatcher(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{pattern: "web-*", name: "web-1", want: true},
+		{pattern: "web-*", name: "db-1", want: false},
+		{pattern: "web-?", name: "web-10", want: false},
+		{pattern: "db", name: "db", want: true},
+		{pattern: "/^web-[0-9]+$/", name: "web-10", want: true},
+		{pattern: "/^web-[0-9]+$/", name: "web-a", want: false},
+		{pattern: "/prod/", name: "api-prod-1", want: true},
+	}
+	for _, tc := range tests {
+		match, err := nameMatcher(tc.pattern)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.pattern, err)
+		}
+		if got := match(tc.name); got != tc.want {
+			t.Errorf("%s matches %s: got %v, want %v", tc.pattern, tc.name, got, tc.want)
+		}
+	}
+	for _, pattern := range []string{"web-[", "/web-(/"} {
+		if _, err := nameMatcher(pattern); err == nil {
+			t.Errorf("%s: got no error", pattern)
+		}
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	for column, want := range map[string]int{"name": 1, "Created At": 10, "created-at": 10, "lan_ip": 7, "ID": 0} {
+		got, err := columnIndex(serverListHeader, column)
+		if err != nil || got != want {
+			t.Errorf("%s: got %d, %v, want %d", column, got, err, want)
+		}
+	}
+	if _, err := columnIndex(serverListHeader, "size"); err == nil {
+		t.Error("size: got no error")
+	}
+}
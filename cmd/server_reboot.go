@@ -0,0 +1,216 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/gobizfly"
+	"github.com/spf13/cobra"
+)
+
+var (
+	rebootHard     bool
+	rebootSelector string
+	rebootParallel int
+
+	serverRebootHeader = []string{"Server", "ID", "Result", "Message"}
+)
+
+// serverRebootResult is the row of a server in the result of server reboot
+type serverRebootResult struct {
+	Server  string `json:"server" yaml:"server"`
+	ID      string `json:"id" yaml:"id"`
+	Result  string `json:"result" yaml:"result"`
+	Message string `json:"message" yaml:"message"`
+
+	err error
+}
+
+// serverRebootCmd represents the reboot server command
+var serverRebootCmd = &cobra.Command{
+	Use:   "reboot",
+	Short: "Reboot servers",
+	Long: `Reboot one or more servers, given by ID or name or selected by their metadata with --selector.
+The reboot is soft unless --hard is given. The servers are rebooted in parallel and the result of each one is listed.
+Use: bizfly server reboot <server> [<server> ...] [--hard]
+Use: bizfly server reboot --selector <key>[=<value>],... [--hard]
+Example: bizfly server reboot web-1 web-2 --hard
+Example: bizfly server reboot --selector role=web --parallel 2`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return rebootServers(cmd, args, rebootHard)
+	},
+}
+
+// serverHardRebootCmd keeps the former "bizfly server hard reboot <server>"
+var serverHardRebootCmd = &cobra.Command{
+	Use:        "hard",
+	Hidden:     true,
+	Deprecated: "use bizfly server reboot --hard <server>",
+	Args:       cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if args[0] != "reboot" {
+			return usageError("Unknown command %q. Use: bizfly server reboot --hard <server>", "hard "+args[0])
+		}
+		return rebootServers(cmd, args[1:], true)
+	},
+}
+
+// rebootServers reboots the servers of args or of --selector and lists the results
+func rebootServers(cmd *cobra.Command, args []string, hard bool) error {
+	if len(args) == 0 && rebootSelector == "" {
+		return usageError("You need to specify the servers or --selector. Use: bizfly server reboot <server> [<server> ...] [--hard]")
+	}
+	if len(args) > 0 && rebootSelector != "" {
+		return usageError("You cannot specify both servers and --selector")
+	}
+	if rebootParallel < 1 {
+		return usageError("Invalid --parallel %d, it must be 1 or more", rebootParallel)
+	}
+	var selector []string
+	if rebootSelector != "" {
+		var err error
+		if selector, err = parseSelector(rebootSelector); err != nil {
+			return err
+		}
+	}
+	client, ctx, err := getApiClient(cmd)
+	if err != nil {
+		return err
+	}
+	var results []*serverRebootResult
+	if selector != nil {
+		if results, err = selectRebootServers(ctx, client, selector); err != nil {
+			return err
+		}
+	} else {
+		for _, arg := range args {
+			result := &serverRebootResult{Server: arg}
+			result.ID, result.err = resolveServer(ctx, client, arg)
+			if result.err != nil && !isNotFound(result.err) {
+				return result.err
+			}
+			results = append(results, result)
+		}
+	}
+	parallel := rebootParallel
+	if dryRun {
+		// the requests are printed one after the other
+		parallel = 1
+	}
+	rebootAll(ctx, client, results, hard, parallel)
+	data := make([][]string, 0, len(results))
+	failed := 0
+	var firstErr error
+	for _, result := range results {
+		data = append(data, []string{result.Server, result.ID, result.Result, result.Message})
+		if result.err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = result.err
+			}
+		}
+	}
+	if err := formatter.Output(serverRebootHeader, data, results); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return &cmdError{code: exitCode(firstErr), err: fmt.Errorf("%d of %d servers failed to reboot", failed, len(results))}
+	}
+	return nil
+}
+
+// parseSelector parses a comma separated list of key or key=value pairs
+func parseSelector(value string) ([]string, error) {
+	var selector []string
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if key, _, _ := strings.Cut(pair, "="); key == "" {
+			return nil, usageError("Invalid --selector %s, e.g. role=web,env=prod", value)
+		}
+		selector = append(selector, pair)
+	}
+	return selector, nil
+}
+
+// selectRebootServers returns the servers whose metadata match a selector
+func selectRebootServers(ctx context.Context, client *gobizfly.Client, selector []string) ([]*serverRebootResult, error) {
+	servers, err := client.CloudServer.List(ctx, &gobizfly.ServerListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	filter := &serverFilter{tags: selector}
+	var results []*serverRebootResult
+	for _, server := range servers {
+		if filter.match(server) {
+			results = append(results, &serverRebootResult{Server: server.Name, ID: server.ID})
+		}
+	}
+	if len(results) == 0 {
+		return nil, notFoundError("No server matches the selector %s", strings.Join(selector, ","))
+	}
+	return results, nil
+}
+
+// rebootAll reboots the servers of results, at most parallel at a time, and
+// sets their result. The servers which are not found are skipped.
+func rebootAll(ctx context.Context, client *gobizfly.Client, results []*serverRebootResult, hard bool, parallel int) {
+	reboot := client.CloudServer.SoftReboot
+	if hard {
+		reboot = client.CloudServer.HardReboot
+	}
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for _, result := range results {
+		if result.err != nil {
+			result.Result, result.Message = "not found", result.err.Error()
+			continue
+		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(result *serverRebootResult) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			res, err := reboot(ctx, result.ID)
+			switch {
+			case errors.Is(err, gobizfly.ErrNotFound):
+				result.Result, result.Message = "not found", fmt.Sprintf("Server %s is not found", result.ID)
+				result.err = notFoundError("%s", result.Message)
+			case err != nil:
+				result.Result, result.Message, result.err = "failed", err.Error(), err
+			default:
+				result.Result, result.Message = "rebooting", res.Message
+			}
+		}(result)
+	}
+	wg.Wait()
+}
+
+func init() {
+	rf := serverRebootCmd.Flags()
+	rf.BoolVar(&rebootHard, "hard", false, "Hard reboot, like a power cycle, instead of a reboot of the OS")
+	rf.StringVarP(&rebootSelector, "selector", "l", "", "Reboot the servers whose metadata match key[=value] pairs separated by commas")
+	rf.IntVar(&rebootParallel, "parallel", 5, "Number of servers rebooted at the same time")
+	serverHardRebootCmd.Flags().AddFlagSet(rf)
+}
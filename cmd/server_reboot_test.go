@@ -0,0 +1,42 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := map[string][]string{
+		"role=web":           {"role=web"},
+		"role=web, env=prod": {"role=web", "env=prod"},
+		"managed":            {"managed"},
+		"role=web,backup=":   {"role=web", "backup="},
+	}
+	for value, want := range tests {
+		got, err := parseSelector(value)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %q, %v, want %q", value, got, err, want)
+		}
+	}
+	for _, value := range []string{"=web", "role=web,", ","} {
+		if _, err := parseSelector(value); err == nil {
+			t.Errorf("%s: got no error", value)
+		}
+	}
+}
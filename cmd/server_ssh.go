@@ -0,0 +1,262 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bizflycloud/bizflyctl/logging"
+	"github.com/bizflycloud/gobizfly"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+)
+
+const defaultSSHUser = "root"
+
+var (
+	sshPrivate  bool
+	sshUser     string
+	sshIdentity string
+	sshJump     string
+	sshPort     int
+)
+
+// sshUsers are the default users of the OS distributions, the others use root
+var sshUsers = map[string]string{
+	"ubuntu":    "ubuntu",
+	"debian":    "debian",
+	"centos":    "centos",
+	"almalinux": "almalinux",
+	"rocky":     "rocky",
+	"fedora":    "fedora",
+	"windows":   "Administrator",
+}
+
+// sshTarget is where ssh connects to
+type sshTarget struct {
+	user     string
+	host     string
+	port     int
+	identity string
+	// jump is the user@host[:port] of the jump host, if any
+	jump string
+}
+
+var serverSSHCmd = &cobra.Command{
+	Use:   "ssh",
+	Short: "Connect to a server with ssh",
+	Long: `Connect to a server with the local ssh client, on its WAN IPv4 address or its LAN address with --private.
+The user is the default one of the OS of the server, e.g. ubuntu, and the key is ~/.ssh/<key name> of the server
+when it exists. A command after -- is run on the server instead of a shell.
+Use: bizfly server ssh <server> [--private] [--user <user>] [--identity <file>] [--jump <host>] [-- <command>]
+Example: bizfly server ssh web-1
+Example: bizfly server ssh db --private --jump web-1 -- sudo systemctl status postgresql`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		command := []string{}
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			command = args[dash:]
+			args = args[:dash]
+		}
+		if len(args) != 1 {
+			return usageError("You need to specify the server. Use: bizfly server ssh <server> [-- <command>]")
+		}
+		if sshPort < 1 || sshPort > 65535 {
+			return usageError("Invalid --port %d, it must be between 1 and 65535", sshPort)
+		}
+		sshPath, err := exec.LookPath("ssh")
+		if err != nil && !dryRun {
+			return fmt.Errorf("ssh is not found, install an OpenSSH client: %w", err)
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		target, err := serverSSHTarget(ctx, client, args[0], sshPrivate)
+		if err != nil {
+			return err
+		}
+		target.port = sshPort
+		if sshUser != "" {
+			target.user = sshUser
+		}
+		if sshIdentity != "" {
+			target.identity = sshIdentity
+		}
+		if sshJump != "" {
+			if target.jump, err = sshJumpHost(ctx, client, sshJump); err != nil {
+				return err
+			}
+		}
+		sshArgs := target.args(command)
+		if dryRun {
+			fmt.Println(strings.Join(append([]string{"ssh"}, sshArgs...), " "))
+			return nil
+		}
+		logging.Verbosef("Running ssh %s", strings.Join(sshArgs, " "))
+		return runSSH(sshPath, sshArgs)
+	},
+}
+
+// serverSSHTarget returns the address, user and key of a server
+func serverSSHTarget(ctx context.Context, client *gobizfly.Client, value string, private bool) (*sshTarget, error) {
+	serverID, err := resolveServer(ctx, client, value)
+	if err != nil {
+		return nil, err
+	}
+	server, err := client.CloudServer.Get(ctx, serverID)
+	if err != nil {
+		if errors.Is(err, gobizfly.ErrNotFound) {
+			return nil, notFoundError("Server %s not found.", value)
+		}
+		return nil, err
+	}
+	target := &sshTarget{user: serverSSHUser(ctx, client, server)}
+	if private {
+		if len(server.IPAddresses.LanAddresses) == 0 {
+			return nil, usageError("Server %s has no LAN address", server.Name)
+		}
+		target.host = server.IPAddresses.LanAddresses[0].Address
+	} else {
+		if len(server.IPAddresses.WanV4Addresses) == 0 {
+			return nil, usageError("Server %s has no WAN IPv4 address. Use --private with --jump to connect through another server", server.Name)
+		}
+		target.host = server.IPAddresses.WanV4Addresses[0].Address
+	}
+	if server.KeyName != "" {
+		target.identity = sshKeyFile(server.KeyName)
+	}
+	return target, nil
+}
+
+// serverSSHUser returns the default user of the OS image of a server, root
+// when the image is not found
+func serverSSHUser(ctx context.Context, client *gobizfly.Client, server *gobizfly.Server) string {
+	images, err := client.CloudServer.OSImages().List(ctx)
+	if err != nil {
+		logging.Verbosef("List os image error: %v, using the user %s", err, defaultSSHUser)
+		return defaultSSHUser
+	}
+	for _, image := range images {
+		for _, version := range image.Version {
+			if version.ID == server.Image.ID {
+				return sshUserOf(image.OSDistribution)
+			}
+		}
+	}
+	logging.Verbosef("The image of server %s is not found, using the user %s", server.Name, defaultSSHUser)
+	return defaultSSHUser
+}
+
+// sshUserOf returns the default user of an OS distribution
+func sshUserOf(distribution string) string {
+	distribution = strings.ToLower(distribution)
+	for name, user := range sshUsers {
+		if strings.Contains(distribution, name) {
+			return user
+		}
+	}
+	return defaultSSHUser
+}
+
+// sshKeyFile returns ~/.ssh/<key name>, or with a .pem extension, when it
+// exists. Otherwise ssh uses its default keys and the agent.
+func sshKeyFile(keyName string) string {
+	home, err := homedir.Dir()
+	if err != nil {
+		return ""
+	}
+	for _, name := range []string{keyName, keyName + ".pem"} {
+		path := filepath.Join(home, ".ssh", name)
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			return path
+		}
+	}
+	return ""
+}
+
+// sshJumpHost returns the jump host of --jump. A host name or user@host is
+// used as is, a server is connected to on its WAN IPv4 address.
+func sshJumpHost(ctx context.Context, client *gobizfly.Client, value string) (string, error) {
+	if strings.ContainsAny(value, "@.:") {
+		return value, nil
+	}
+	target, err := serverSSHTarget(ctx, client, value, false)
+	if isNotFound(err) {
+		return value, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return target.user + "@" + target.host, nil
+}
+
+// args returns the arguments of ssh
+func (t *sshTarget) args(command []string) []string {
+	var args []string
+	if t.identity != "" {
+		args = append(args, "-i", t.identity)
+	}
+	if t.port != 0 && t.port != 22 {
+		args = append(args, "-p", strconv.Itoa(t.port))
+	}
+	if t.jump != "" {
+		args = append(args, "-J", t.jump)
+	}
+	args = append(args, t.user+"@"+t.host)
+	if len(command) > 0 {
+		args = append(append(args, "--"), command...)
+	}
+	return args
+}
+
+// runSSH runs ssh attached to the terminal and exits with its exit code
+func runSSH(path string, args []string) error {
+	c := exec.Command(path, args...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	// ssh handles Ctrl-C itself, do not exit before it
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+	if err := c.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitStatus(exitErr.ExitCode())
+		}
+		return err
+	}
+	return nil
+}
+
+func init() {
+	sf := serverSSHCmd.Flags()
+	sf.BoolVar(&sshPrivate, "private", false, "Connect to the LAN address instead of the WAN IPv4 address")
+	sf.StringVarP(&sshUser, "user", "l", "", "User to log in as, default: the default user of the OS of the server")
+	sf.StringVarP(&sshIdentity, "identity", "i", "", "Private key file, default: ~/.ssh/<key name of the server> when it exists")
+	sf.StringVarP(&sshJump, "jump", "J", "", "Jump host, a server name or ID or [user@]host[:port]")
+	sf.IntVarP(&sshPort, "port", "p", 22, "SSH port of the server")
+	serverCmd.AddCommand(serverSSHCmd)
+}
@@ -0,0 +1,54 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSSHTargetArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  sshTarget
+		command []string
+		want    []string
+	}{
+		{name: "shell", target: sshTarget{user: "ubuntu", host: "103.56.156.11", port: 22},
+			want: []string{"ubuntu@103.56.156.11"}},
+		{name: "key and port", target: sshTarget{user: "root", host: "103.56.156.11", port: 2222, identity: "/home/u/.ssh/deploy"},
+			want: []string{"-i", "/home/u/.ssh/deploy", "-p", "2222", "root@103.56.156.11"}},
+		{name: "jump and command", target: sshTarget{user: "debian", host: "10.20.0.21", port: 22, jump: "ubuntu@103.56.156.11"},
+			command: []string{"uptime", "-p"}, want: []string{"-J", "ubuntu@103.56.156.11", "debian@10.20.0.21", "--", "uptime", "-p"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.target.args(tc.command); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSSHUserOf(t *testing.T) {
+	for distribution, want := range map[string]string{"Ubuntu": "ubuntu", "debian": "debian", "CentOS": "centos",
+		"AlmaLinux": "almalinux", "Windows": "Administrator", "freebsd": "root", "": "root"} {
+		if got := sshUserOf(distribution); got != want {
+			t.Errorf("%q: got %s, want %s", distribution, got, want)
+		}
+	}
+}
@@ -18,12 +18,11 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -40,7 +39,7 @@ var snapshotCmd = &cobra.Command{
 	Short: "Bizfly Cloud Snapshot Interaction",
 	Long:  `Bizfly Cloud Server Action: Create, List, Delete, Snapshot`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("snapshot called")
+		logging.Debugf("snapshot called")
 	},
 }
 
@@ -50,13 +49,15 @@ var createSnapshotCmd = &cobra.Command{
 	Short: "Create a new snapshot",
 	Long: `Create a new snapshot
 Exmaple: bizfly snapshot create <volume_id> --name snapshot-name`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify volume-id in the command. Use bizfly snapshot create <volume-id> --name <snapshot-name>")
-			os.Exit(1)
+			return usageError("You need to specify volume-id in the command. Use bizfly snapshot create <volume-id> --name <snapshot-name>")
 		}
 		volumeID := args[0]
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		scr := gobizfly.SnapshotCreateRequest{
 			Name:     snapshotName,
 			VolumeID: volumeID,
@@ -64,13 +65,12 @@ Exmaple: bizfly snapshot create <volume_id> --name snapshot-name`,
 		}
 		snap, err := client.CloudServer.Snapshots().Create(ctx, &scr)
 		if err != nil {
-			fmt.Printf("Create snapshot for volume %s error %v", volumeID, err)
-			os.Exit(1)
+			return fmt.Errorf("Create snapshot for volume %s error %w", volumeID, err)
 		}
 		var data [][]string
 		data = append(data, []string{snap.ID, snap.Name, snap.Status, strconv.Itoa(snap.Size),
 			snap.VolumeTypeID, snap.CreateAt, snap.VolumeID, snap.BillingPlan, snap.ZoneName})
-		formatter.Output(snapshotHeaderList, data)
+		return formatter.Output(snapshotHeaderList, data, snap)
 	},
 }
 
@@ -80,18 +80,21 @@ var deleteSnapshotCmd = &cobra.Command{
 	Short: "Delete snapshots",
 	Long: `Delete a snapshot or list of snapshots.
 Example: bizfly snapshot delete <snapshot_id> <snapshot_id>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		for _, snapshotID := range args {
-			fmt.Printf("Deleting snapshot %s \n", snapshotID)
+			logging.Infof("Deleting snapshot %s", snapshotID)
 			err := client.CloudServer.Snapshots().Delete(ctx, snapshotID)
 			if err != nil {
 				if errors.Is(err, gobizfly.ErrNotFound) {
-					fmt.Printf("Snapshot %s is not found", snapshotID)
-					return
+					return notFoundError("Snapshot %s is not found", snapshotID)
 				}
 			}
 		}
+		return nil
 	},
 }
 
@@ -101,24 +104,26 @@ var getSnapshotCmd = &cobra.Command{
 	Short: "Get detail a snapshot",
 	Long: `Get detail a snapshot
 Example: bizfly snapshot get <snapshot_id>`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 
 		snap, err := client.CloudServer.Snapshots().Get(ctx, args[0])
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Snapshot %s not found.", args[0])
-				return
+				return notFoundError("Snapshot %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{snap.ID, snap.Name, snap.Status, strconv.Itoa(snap.Size),
 			snap.VolumeTypeID, snap.CreateAt, snap.VolumeID, snap.BillingPlan, snap.ZoneName})
-		formatter.Output(snapshotHeaderList, data)
+		return formatter.Output(snapshotHeaderList, data, snap)
 	},
 }
 
@@ -128,15 +133,18 @@ var listSnapshotCmd = &cobra.Command{
 	Long: `List all snapshots in your account
 Example: bizfly snapshot list
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		opts := &gobizfly.ListSnasphotsOptions{}
 		if volumeID != "" {
 			opts.VolumeID = volumeID
 		}
 		snapshots, err := client.CloudServer.Snapshots().List(ctx, opts)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, snap := range snapshots {
@@ -144,7 +152,7 @@ Example: bizfly snapshot list
 				snap.ID, snap.Name, snap.Status, strconv.Itoa(snap.Size), snap.VolumeTypeID, snap.CreateAt,
 				snap.VolumeID, snap.BillingPlan, snap.ZoneName})
 		}
-		formatter.Output(snapshotHeaderList, data)
+		return formatter.Output(snapshotHeaderList, data, snapshots)
 	},
 }
 
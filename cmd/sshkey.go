@@ -19,11 +19,11 @@ package cmd
 import (
 	"bufio"
 	"fmt"
-	"log"
 	"os"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -39,7 +39,7 @@ var sshKeyCmd = &cobra.Command{
 	Short: "Bizfly Cloud SSH Key Interaction",
 	Long:  `Bizfly Cloud SSH Key Action: Create, List, Delete`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("SSH Key called")
+		logging.Debugf("SSH Key called")
 	},
 }
 
@@ -47,18 +47,21 @@ var sshkeyListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List your SSH keys",
 	Long:  "List your SSH keys",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		keys, err := client.CloudServer.SSHKeys().List(ctx, &gobizfly.ListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, key := range keys {
 			s := []string{key.SSHKeyPair.Name, key.SSHKeyPair.FingerPrint}
 			data = append(data, s)
 		}
-		formatter.Output(sshListHeader, data)
+		return formatter.Output(sshListHeader, data, keys)
 	},
 }
 
@@ -66,17 +69,24 @@ var sshKeyDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete your SSH key",
 	Long:  "Delete a SSH Key using its name",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("Invalid arguments")
-			os.Exit(1)
+			return usageError("Invalid arguments")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		_, err := client.CloudServer.SSHKeys().Delete(ctx, args[0])
+		keyName, err := resolveSSHKey(ctx, client, args[0])
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("Deleted the SSH key")
+		_, err = client.CloudServer.SSHKeys().Delete(ctx, keyName)
+		if err != nil {
+			return err
+		}
+		logging.Infof("Deleted the SSH key")
+		return nil
 	},
 }
 
@@ -89,14 +99,17 @@ Example 2: bizfly ssh-key create --name test1312 --public-key "your-public-key"
 Example 2: bizfly ssh-key create --name abcxyz --public-key prompt => Paste your public key, and then send EOF (Ctrl + D in *nix; Ctrl + Z in Windows)
 `,
 
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		content, err := os.ReadFile(publicKey)
 		if err == nil {
 			publicKey = string(content)
 		}
 		if publicKey == "prompt" {
-			fmt.Println("Type your SSH-Key:")
+			fmt.Fprintln(os.Stderr, "Type your SSH-Key:")
 			scanner := bufio.NewScanner(os.Stdin)
 			var lines []string
 			for scanner.Scan() {
@@ -104,18 +117,18 @@ Example 2: bizfly ssh-key create --name abcxyz --public-key prompt => Paste your
 				lines = append(lines, line)
 			}
 			publicKey = strings.Join(lines, "")
-			fmt.Println("\nYour public key you typed is: ", publicKey)
+			logging.Infof("Your public key you typed is: %s", publicKey)
 		}
 		key, err := client.CloudServer.SSHKeys().Create(ctx, &gobizfly.SSHKeyCreateRequest{
 			Name:      sshKeyName,
 			PublicKey: publicKey,
 		})
 		if err != nil {
-			log.Fatal(err)
+			return err
 
 		}
 		data := [][]string{{key.Name, key.FingerPrint}}
-		formatter.Output(sshListHeader, data)
+		return formatter.Output(sshListHeader, data, key)
 	},
 }
 
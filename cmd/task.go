@@ -0,0 +1,126 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
+	"github.com/bizflycloud/gobizfly"
+	"github.com/spf13/cobra"
+)
+
+var taskDetailHeader = []string{"Task ID", "Action", "State", "Progress", "Server ID", "Server Name", "Server Status"}
+
+// taskCmd represents the task command
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Bizfly Cloud Task Interaction",
+	Long: `Inspect the tasks returned by long running commands such as server create and server delete.
+Use: bizfly task get <task-id>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logging.Debugf("task called")
+	},
+}
+
+// taskGetCmd represents the get task command
+var taskGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get the state of a task",
+	Long: `
+Get the progress, the result and the server of a task.
+Use: bizfly task get <task-id>
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return usageError("You need to specify task-id in the command. Use: bizfly task get <task-id>")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		task, err := getTask(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		return outputTask(args[0], task)
+	},
+}
+
+// taskWatchCmd represents the watch task command
+var taskWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch a task until it is finished",
+	Long: `
+Print the progress of a task until it is finished, then print its result.
+Exit with a non-zero code when the task fails.
+Use: bizfly task watch <task-id>
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 1 {
+			return usageError("You need to specify task-id in the command. Use: bizfly task watch <task-id>")
+		}
+		taskID := args[0]
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		if _, err := getTask(ctx, client, taskID); err != nil {
+			return err
+		}
+		var task *gobizfly.Task
+		err = poll(ctx, "Task "+taskID, taskCheck(client, taskID, &task))
+		if task != nil {
+			if outErr := outputTask(taskID, task); err == nil {
+				err = outErr
+			}
+		}
+		return err
+	},
+}
+
+// getTask returns a task, reporting a missing task as not found
+func getTask(ctx context.Context, client *gobizfly.Client, taskID string) (*gobizfly.Task, error) {
+	task, err := client.CloudServer.GetTask(ctx, taskID)
+	if errors.Is(err, gobizfly.ErrNotFound) {
+		return nil, notFoundError("Task %s is not found", taskID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Get task error: %w", err)
+	}
+	return task, nil
+}
+
+func outputTask(taskID string, task *gobizfly.Task) error {
+	data := [][]string{{
+		taskID, task.Result.Action, taskState(task), strconv.Itoa(task.Result.Progress) + "%",
+		task.Result.ID, task.Result.Name, task.Result.Status,
+	}}
+	return formatter.Output(taskDetailHeader, data, task)
+}
+
+func init() {
+	rootCmd.AddCommand(taskCmd)
+	taskCmd.AddCommand(taskGetCmd)
+
+	addPollFlags(taskWatchCmd, "Interval between the status checks")
+	taskCmd.AddCommand(taskWatchCmd)
+}
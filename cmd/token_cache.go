@@ -0,0 +1,195 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/bizflycloud/gobizfly"
+)
+
+const (
+	// a cached token is renewed when it expires within tokenRenewBefore
+	tokenRenewBefore = 5 * time.Minute
+	// defaultTokenTTL is used when the API does not return the expiry of a token
+	defaultTokenTTL = time.Hour
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// tokenCacheEntry is the content of a token cache file
+type tokenCacheEntry struct {
+	Identity  string          `json:"identity"`
+	Token     *gobizfly.Token `json:"token"`
+	ExpiresAt time.Time       `json:"expires_at"`
+}
+
+// tokenCache stores the token of one profile and project on disk
+type tokenCache struct {
+	path     string
+	identity string
+}
+
+func newTokenCache(profile, regionName, projectID, identity string) *tokenCache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil
+	}
+	if profile == "" {
+		profile = defaultProfile
+	}
+	if projectID == "" {
+		projectID = "default"
+	}
+	name := unsafeFileChars.ReplaceAllString(regionName+"_"+projectID, "_") + ".json"
+	return &tokenCache{
+		path:     filepath.Join(dir, "bizfly", "tokens", unsafeFileChars.ReplaceAllString(profile, "_"), name),
+		identity: identity,
+	}
+}
+
+// Load returns the cached token if it is still valid for a while
+func (c *tokenCache) Load() *gobizfly.Token {
+	if c == nil {
+		return nil
+	}
+	b, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil
+	}
+	var entry tokenCacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil
+	}
+	if entry.Token == nil || entry.Token.KeystoneToken == "" || entry.Identity != c.identity {
+		return nil
+	}
+	if time.Now().Add(tokenRenewBefore).After(entry.ExpiresAt) {
+		return nil
+	}
+	return entry.Token
+}
+
+// Save writes the token to the cache file, readable by the current user only
+func (c *tokenCache) Save(tok *gobizfly.Token) error {
+	// --dry-run writes nothing, not even the cache
+	if c == nil || dryRun {
+		return nil
+	}
+	b, err := json.Marshal(tokenCacheEntry{Identity: c.identity, Token: tok, ExpiresAt: tokenExpiry(tok)})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// Remove deletes the cache file
+func (c *tokenCache) Remove() error {
+	if c == nil || dryRun {
+		return nil
+	}
+	if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// removeProfileTokenCache deletes the cached tokens of all projects of a profile
+func removeProfileTokenCache(profile string) error {
+	if dryRun {
+		return nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(filepath.Join(dir, "bizfly", "tokens", unsafeFileChars.ReplaceAllString(profile, "_")))
+}
+
+func tokenExpiry(tok *gobizfly.Token) time.Time {
+	if t, ok := parseTokenExpiry(tok.ExpiresAt); ok {
+		return t
+	}
+	return time.Now().Add(defaultTokenTTL)
+}
+
+// parseTokenExpiry parses the expiry the API returns with a token
+func parseTokenExpiry(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000000Z", "2006-01-02T15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// reauthTransport retries a request once with a new token when the API
+// rejects the token of the request, e.g. a cached token which was revoked.
+type reauthTransport struct {
+	base    http.RoundTripper
+	mu      sync.Mutex
+	refresh func(ctx context.Context) (string, error)
+}
+
+func (t *reauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || req.Header.Get("X-Auth-Token") == "" {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		return resp, nil
+	}
+	// refresh only once per run, a rejected new token means wrong credentials
+	t.mu.Lock()
+	refresh := t.refresh
+	t.refresh = nil
+	t.mu.Unlock()
+	if refresh == nil {
+		return resp, nil
+	}
+	token, rerr := refresh(req.Context())
+	if rerr != nil {
+		return resp, nil
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	retry.Header.Set("X-Auth-Token", token)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	return t.base.RoundTrip(retry)
+}
@@ -18,12 +18,11 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -46,7 +45,7 @@ var volumeCmd = &cobra.Command{
 	Short: "Bizfly Cloud Volume Interaction",
 	Long:  `Bizfly Cloud Volume Action: Create, List, Delete, Extend Volume`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("volume called")
+		logging.Debugf("volume called")
 	},
 }
 
@@ -59,18 +58,26 @@ Example: bizfly volume delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58
 
 You can delete multiple volumes with list of volume ID
 Example: bizfly volume delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58 f5869e9c-9ab2-11ea-b9e3-e353a4f04836`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
-		for _, volumeID := range args {
-			fmt.Printf("Deleting volume %s \n", volumeID)
-			err := client.CloudServer.Volumes().Delete(ctx, volumeID)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		for _, arg := range args {
+			volumeID, err := resolveVolume(ctx, client, arg)
+			if err != nil {
+				return err
+			}
+			logging.Infof("Deleting volume %s", volumeID)
+			err = client.CloudServer.Volumes().Delete(ctx, volumeID)
 			if err != nil {
 				if errors.Is(err, gobizfly.ErrNotFound) {
-					fmt.Printf("Volume %s is not found", volumeID)
-					return
+					return notFoundError("Volume %s is not found", volumeID)
 				}
+				return err
 			}
 		}
+		return nil
 	},
 }
 
@@ -81,19 +88,24 @@ var volumeGetCmd = &cobra.Command{
 	Long: `Get detail a volume in your account
 Example: bizfly volume get 9e580b1a-0526-460b-9a6f-d8f80130bda8
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
 		}
-		client, ctx := getApiClient(cmd)
-
-		volume, err := client.CloudServer.Volumes().Get(ctx, args[0])
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		volumeID, err := resolveVolume(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		volume, err := client.CloudServer.Volumes().Get(ctx, volumeID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Volume %s not found.", args[0])
-				return
+				return notFoundError("Volume %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		serverID := ""
@@ -103,7 +115,7 @@ Example: bizfly volume get 9e580b1a-0526-460b-9a6f-d8f80130bda8
 		data = append(data, []string{volume.ID, volume.Name, volume.Description, volume.Status,
 			strconv.Itoa(volume.Size), volume.CreatedAt, volume.VolumeType, volume.SnapshotID, volume.BillingPlan,
 			volume.AvailabilityZone, serverID})
-		formatter.Output(volumeHeaderList, data)
+		return formatter.Output(volumeHeaderList, data, volume)
 	},
 }
 
@@ -114,11 +126,14 @@ var volumeListCmd = &cobra.Command{
 	Long: `List all volumes in your Bizfly Cloud account
 Example: bizfly volume list
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		volumes, err := client.CloudServer.Volumes().List(ctx, &gobizfly.VolumeListOptions{})
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, volume := range volumes {
@@ -130,7 +145,7 @@ Example: bizfly volume list
 				strconv.Itoa(volume.Size), volume.CreatedAt, volume.VolumeType, volume.SnapshotID, volume.BillingPlan,
 				volume.AvailabilityZone, serverID})
 		}
-		formatter.Output(volumeHeaderList, data)
+		return formatter.Output(volumeHeaderList, data, volumes)
 	},
 }
 
@@ -142,8 +157,11 @@ var volumeCreateCmd = &cobra.Command{
 Create a new volume
 Use: bizfly volume create
 `,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		vcr := gobizfly.VolumeCreateRequest{
 			Name:             volumeName,
 			Size:             volumeSize,
@@ -155,10 +173,14 @@ Use: bizfly volume create
 			Description:      description,
 			BillingPlan:      volumeBillingPlan,
 		}
+		if serverID != "" {
+			if vcr.ServerID, err = resolveServer(ctx, client, serverID); err != nil {
+				return err
+			}
+		}
 		volume, err := client.CloudServer.Volumes().Create(ctx, &vcr)
 		if err != nil {
-			fmt.Printf("Create a new volume error: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Create a new volume error: %w", err)
 		}
 		var data [][]string
 		serverID := ""
@@ -168,7 +190,14 @@ Use: bizfly volume create
 		data = append(data, []string{volume.ID, volume.Name, volume.Description, volume.Status,
 			strconv.Itoa(volume.Size), volume.CreatedAt, volume.VolumeType, volume.SnapshotID, volume.BillingPlan,
 			volume.AvailabilityZone, serverID})
-		formatter.Output(volumeHeaderList, data)
+		if err := formatter.Output(volumeHeaderList, data, volume); err != nil {
+			return err
+		}
+		targets := volumeReadyStatus
+		if vcr.ServerID != "" {
+			targets = volumeAttachedStatus
+		}
+		return waitForStatus(ctx, "Volume "+volume.ID, volumeStatus(client, volume.ID), targets...)
 	},
 }
 
@@ -180,28 +209,28 @@ var volumeAttachCmd = &cobra.Command{
 Attach a volume to a server
 Use: bizfly volume attach <volume-id> <server-id>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 2 {
-			fmt.Println("Command error: use bizfly volume attach <volume-id> <server-id>")
-			os.Exit(1)
+			return usageError("You need to specify the volume and the server. Use: bizfly volume attach <volume-id> <server-id>")
 		}
-		volumeID := args[0]
-		if volumeID == "" {
-			fmt.Println("You need to specify volume-id in the command")
-			os.Exit(1)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		serverID := args[1]
-		if serverID == "" {
-			fmt.Println("You need to specify server-id in the command")
-			os.Exit(1)
+		volumeID, err := resolveVolume(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		serverID, err := resolveServer(ctx, client, args[1])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		res, err := client.CloudServer.Volumes().Attach(ctx, volumeID, serverID)
 		if err != nil {
-			fmt.Printf("Attach a volume to a server error: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Attach a volume to a server error: %w", err)
 		}
-		fmt.Println(res.Message)
+		logging.Infof("%s", res.Message)
+		return waitForStatus(ctx, "Volume "+volumeID, volumeStatus(client, volumeID), volumeAttachedStatus...)
 	},
 }
 
@@ -213,28 +242,28 @@ var volumeDetachCmd = &cobra.Command{
 Detach a volume from a server
 Use: bizfly volume detach <volume-id> <server-id>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 2 {
-			fmt.Println("Command error: use bizfly volume attach <volume-id> <server-id>")
-			os.Exit(1)
+			return usageError("You need to specify the volume and the server. Use: bizfly volume detach <volume-id> <server-id>")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		volumeID := args[0]
-		if volumeID == "" {
-			fmt.Println("You need to specify volume-id in the command")
-			os.Exit(1)
+		volumeID, err := resolveVolume(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		serverID := args[1]
-		if serverID == "" {
-			fmt.Println("You need to specify server-id in the command")
-			os.Exit(1)
+		serverID, err := resolveServer(ctx, client, args[1])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		res, err := client.CloudServer.Volumes().Detach(ctx, volumeID, serverID)
 		if err != nil {
-			fmt.Printf("Detach a volume from a server error: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Detach a volume from a server error: %w", err)
 		}
-		fmt.Println(res.Message)
+		logging.Infof("%s", res.Message)
+		return nil
 	},
 }
 
@@ -246,18 +275,24 @@ var extendVolumeCmd = &cobra.Command{
 Extend size of a volume
 Use: bizfly volume extend <volume-id> --size <new-size>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify the volume-id in the command. Use: bizfly volume extend <volume-id> --size <new size>")
-			os.Exit(1)
+			return usageError("You need to specify the volume-id in the command. Use: bizfly volume extend <volume-id> --size <new size>")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		volumeID := args[0]
-		client, ctx := getApiClient(cmd)
-		_, err := client.CloudServer.Volumes().ExtendVolume(ctx, volumeID, volumeSize)
+		volumeID, err := resolveVolume(ctx, client, args[0])
 		if err != nil {
-			fmt.Printf("Extend volume error: %v\n", err)
+			return err
 		}
-		fmt.Printf("Extending volume %v\n", volumeID)
+		_, err = client.CloudServer.Volumes().ExtendVolume(ctx, volumeID, volumeSize)
+		if err != nil {
+			return fmt.Errorf("Extend volume error: %w", err)
+		}
+		logging.Infof("Extending volume %v", volumeID)
+		return waitForUpdate(ctx, "Volume "+volumeID, volumeSizeUpdate(client, volumeID, volumeSize), volumeReadyStatus...)
 	},
 }
 
@@ -268,18 +303,24 @@ var restoreVolumeCmd = &cobra.Command{
 Restore volume by using its snapshot
 Use: bizfly volume restore <volume-id> --snapshot-id <snapshot-id>
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify the volume-id in the command. Use: bizfly volume restore <volume-id> --snapshot-id <snapshot-id>")
-			os.Exit(1)
+			return usageError("You need to specify the volume-id in the command. Use: bizfly volume restore <volume-id> --snapshot-id <snapshot-id>")
 		}
-		volumeID := args[0]
-		client, ctx := getApiClient(cmd)
-		_, err := client.CloudServer.Volumes().Restore(ctx, volumeID, snapshotID)
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Printf("Restoring volume %s using snapshot %s", volumeID, snapshotID)
+		volumeID, err := resolveVolume(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		_, err = client.CloudServer.Volumes().Restore(ctx, volumeID, snapshotID)
+		if err != nil {
+			return err
+		}
+		logging.Infof("Restoring volume %s using snapshot %s", volumeID, snapshotID)
+		return nil
 	},
 }
 
@@ -289,17 +330,23 @@ var patchVolumeCmd = &cobra.Command{
 	Long: `
 Patch volume
 Use: bizfly volume patch <volume-id> [--name <vol_name>] [--description <description>]`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			log.Fatal("You need to specify the volume-id in the command. Use: bizfly volume patch <volume-id> [--name <vol_name>] [--description <description>]")
+			return usageError("You need to specify the volume-id in the command. Use: bizfly volume patch <volume-id> [--name <vol_name>] [--description <description>]")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		volumeID, err := resolveVolume(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		volumeID := args[0]
-		client, ctx := getApiClient(cmd)
 		req := &gobizfly.VolumePatchRequest{}
 		req.Description = description
 		volume, err := client.CloudServer.Volumes().Patch(ctx, volumeID, req)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		serverID := ""
@@ -309,7 +356,7 @@ Use: bizfly volume patch <volume-id> [--name <vol_name>] [--description <descrip
 		data = append(data, []string{volume.ID, volume.Name, volume.Description, volume.Status,
 			strconv.Itoa(volume.Size), volume.CreatedAt, volume.VolumeType, volume.SnapshotID, volume.BillingPlan,
 			volume.AvailabilityZone, serverID})
-		formatter.Output(volumeHeaderList, data)
+		return formatter.Output(volumeHeaderList, data, volume)
 	},
 }
 
@@ -319,8 +366,11 @@ var listVolumeTypesCmd = &cobra.Command{
 	Long: `
 List volume types
 Use: bizfly volume list-types --category <category> --availability-zone <availability-zone>`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		opts := &gobizfly.ListVolumeTypesOptions{}
 		if category != "" {
 			opts.Category = category
@@ -330,14 +380,14 @@ Use: bizfly volume list-types --category <category> --availability-zone <availab
 		}
 		volumeTypes, err := client.CloudServer.Volumes().ListVolumeTypes(ctx, opts)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, volumeType := range volumeTypes {
 			data = append(data, []string{volumeType.Type, volumeType.Category,
 				strings.Join(volumeType.AvailabilityZones, ",")})
 		}
-		formatter.Output(volumeTypeHeaderList, data)
+		return formatter.Output(volumeTypeHeaderList, data, volumeTypes)
 	},
 }
 
@@ -363,14 +413,17 @@ func init() {
 	vcpf.StringVar(&snapshotID, "snapshot-id", "", "Create a volume from a snapshot")
 	vcpf.StringVar(&serverID, "server-id", "", "Create a new volume and attach to a server")
 	vcpf.StringVar(&volumeBillingPlan, "billing-plan", "saving_plan", "Billing plan of volume: saving_plan, on_demand")
+	addWaitFlags(volumeCreateCmd)
 	volumeCmd.AddCommand(volumeCreateCmd)
 
+	addWaitFlags(volumeAttachCmd)
 	volumeCmd.AddCommand(volumeAttachCmd)
 
 	volumeCmd.AddCommand(volumeDetachCmd)
 
 	extendVolumeCmd.PersistentFlags().IntVar(&volumeSize, "size", 0, "Volume size")
 	_ = cobra.MarkFlagRequired(extendVolumeCmd.PersistentFlags(), "size")
+	addWaitFlags(extendVolumeCmd)
 	volumeCmd.AddCommand(extendVolumeCmd)
 	pvpf := patchVolumeCmd.PersistentFlags()
 	pvpf.StringVar(&description, "description", "", "Patched volume description")
@@ -18,12 +18,11 @@ package cmd
 import (
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"strconv"
 	"strings"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -42,7 +41,7 @@ var vpcCmd = &cobra.Command{
 	Short: "Bizfly Virtual Private Network Interaction",
 	Long:  "Bizfly Virtual Private Network Action: Create, List, Delete, Update",
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("vpc called")
+		logging.Debugf("vpc called")
 	},
 }
 
@@ -51,22 +50,53 @@ var vpcDeleteCmd = &cobra.Command{
 	Short: "Delete VPC",
 	Long: `Delete VPC with vpc ID as input
 Example: bizfly vpc delete fd554aac-9ab1-11ea-b09d-bbaf82f02f58`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			fmt.Printf("Unknow variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknow variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		vpcID, err := resolveVPC(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		vpc, err := client.CloudServer.VPCNetworks().Get(ctx, vpcID)
+		if err != nil {
+			if errors.Is(err, gobizfly.ErrNotFound) {
+				return notFoundError("VPC %s is not found", vpcID)
+			}
+			return err
+		}
+		details := []string{fmt.Sprintf("VPC %s (%s)", vpc.Name, vpc.ID)}
+		if len(vpc.Subnets) > 0 {
+			details = append(details, "  CIDR "+vpc.Subnets[0].CIDR)
+		}
+		// the clusters are listed on a best effort basis, the project may not use Kubernetes
+		if clusters, err := client.KubernetesEngine.List(ctx, &gobizfly.ListOptions{}); err == nil {
+			for _, cluster := range clusters {
+				if cluster.VPCNetworkID == vpcID {
+					details = append(details, fmt.Sprintf("  used by Kubernetes cluster %s (%s)", cluster.Name, cluster.UID))
+				}
+			}
+		} else {
+			logging.Verbosef("List the Kubernetes clusters of the VPC error: %v", err)
+		}
+		if err := confirmDestroy("The VPC will be deleted:", details); err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 
-		fmt.Printf("Deleting VPC: %v", vpcID)
-		err := client.CloudServer.VPCNetworks().Delete(ctx, vpcID)
+		logging.Infof("Deleting VPC: %v", vpcID)
+		err = client.CloudServer.VPCNetworks().Delete(ctx, vpcID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("VPC %s is not found", serverID)
+				return notFoundError("VPC %s is not found", vpcID)
 			} else {
-				fmt.Printf("Error when delete VPC %v", err)
-				return
+				return fmt.Errorf("Error when delete VPC %w", err)
 			}
 		}
+		return nil
 	},
 }
 
@@ -74,11 +104,14 @@ var vpcListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all vpcs in your account",
 	Long:  "List all vpcs in your account",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		vpcs, err := client.CloudServer.VPCNetworks().List(ctx)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, vpc := range vpcs {
@@ -87,7 +120,7 @@ var vpcListCmd = &cobra.Command{
 				strings.Join(vpc.AvailabilityZoneHints, ", ")}
 			data = append(data, s)
 		}
-		formatter.Output(vpcListHeader, data)
+		return formatter.Output(vpcListHeader, data, vpcs)
 	},
 }
 
@@ -96,25 +129,31 @@ var vpcGetCmd = &cobra.Command{
 	Short: "Get a VPC",
 	Long: `Get detail a VPC with VPC ID as input
 Example: bizfly vpc get fd554aac-9ab1-11ea-b09d-bbaf82f02f58`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
-			fmt.Printf("Unknown variable %s", strings.Join(args[1:], ""))
+			return usageError("Unknown variable %s", strings.Join(args[1:], ""))
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		vpc, err := client.CloudServer.VPCNetworks().Get(ctx, args[0])
+		vpcID, err := resolveVPC(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		vpc, err := client.CloudServer.VPCNetworks().Get(ctx, vpcID)
 		if err != nil {
 			if errors.Is(err, gobizfly.ErrNotFound) {
-				fmt.Printf("Server %s not found.", args[0])
-				return
+				return notFoundError("VPC %s not found.", args[0])
 			}
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		s := []string{vpc.ID, vpc.Name, strconv.Itoa(vpc.MTU), vpc.Subnets[0].CIDR, vpc.Description,
 			strings.Join(vpc.Tags, ", "), vpc.CreatedAt, strconv.FormatBool(vpc.IsDefault),
 			strings.Join(vpc.AvailabilityZoneHints, ", ")}
 		data = append(data, s)
-		formatter.Output(vpcListHeader, data)
+		return formatter.Output(vpcListHeader, data, vpc)
 	},
 }
 
@@ -122,9 +161,9 @@ var vpcCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a VPC",
 	Long:  "Create a new VPC, return its properties",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if vpcName == "" {
-			fmt.Println("You need to specify VPC name to create a new VPC")
+			return usageError("You need to specify VPC name to create a new VPC")
 		}
 		cvpl := gobizfly.CreateVPCPayload{
 			Name:        vpcName,
@@ -132,19 +171,21 @@ var vpcCreateCmd = &cobra.Command{
 			CIDR:        cidr,
 			IsDefault:   isDefault,
 		}
-		client, ctx := getApiClient(cmd)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		vpc, err := client.CloudServer.VPCNetworks().Create(ctx, &cvpl)
 		if err != nil {
-			fmt.Printf("Create VPC error: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Create VPC error: %w", err)
 		}
-		fmt.Printf("Create VPC successfully\n")
+		logging.Infof("Create VPC successfully")
 		var data [][]string
 		s := []string{vpc.ID, vpc.Name, strconv.Itoa(vpc.MTU), vpc.Subnets[0].CIDR, vpc.Description,
 			strings.Join(vpc.Tags, ", "), vpc.CreatedAt, strconv.FormatBool(vpc.IsDefault),
 			strings.Join(vpc.AvailabilityZoneHints, ", ")}
 		data = append(data, s)
-		formatter.Output(vpcListHeader, data)
+		return formatter.Output(vpcListHeader, data, vpc)
 	},
 }
 
@@ -152,9 +193,9 @@ var vpcUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update a VPC",
 	Long:  "Update a VPC",
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) < 1 {
-			fmt.Println("You need to specify vpc-id in the command. Use bizfly vpc update <vpc-id> ...")
+			return usageError("You need to specify vpc-id in the command. Use bizfly vpc update <vpc-id> ...")
 		}
 		uvpl := gobizfly.UpdateVPCPayload{
 			Name:        vpcName,
@@ -162,19 +203,25 @@ var vpcUpdateCmd = &cobra.Command{
 			CIDR:        cidr,
 			IsDefault:   isDefault,
 		}
-		client, ctx := getApiClient(cmd)
-		vpc, err := client.CloudServer.VPCNetworks().Update(ctx, args[0], &uvpl)
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		vpcID, err := resolveVPC(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		vpc, err := client.CloudServer.VPCNetworks().Update(ctx, vpcID, &uvpl)
 		if err != nil {
-			fmt.Printf("Update VPC error: %v", err)
-			os.Exit(1)
+			return fmt.Errorf("Update VPC error: %w", err)
 		}
-		fmt.Printf("Update VPC successfully\n")
+		logging.Infof("Update VPC successfully")
 		var data [][]string
 		s := []string{vpc.ID, vpc.Name, strconv.Itoa(vpc.MTU), vpc.Subnets[0].CIDR, vpc.Description,
 			strings.Join(vpc.Tags, ", "), vpc.CreatedAt, strconv.FormatBool(vpc.IsDefault),
 			strings.Join(vpc.AvailabilityZoneHints, ", ")}
 		data = append(data, s)
-		formatter.Output(vpcListHeader, data)
+		return formatter.Output(vpcListHeader, data, vpc)
 	},
 }
 
@@ -182,6 +229,7 @@ func init() {
 	rootCmd.AddCommand(vpcCmd)
 	vpcCmd.AddCommand(vpcListCmd)
 	vpcCmd.AddCommand(vpcGetCmd)
+	addConfirmFlag(vpcDeleteCmd)
 	vpcCmd.AddCommand(vpcDeleteCmd)
 
 	vcpf := vpcCreateCmd.PersistentFlags()
@@ -0,0 +1,393 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bizflycloud/bizflyctl/logging"
+	"github.com/bizflycloud/gobizfly"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultWaitTimeout  = 30 * time.Minute
+	defaultPollInterval = 5 * time.Second
+	// kafkaAppearTimeout is how long --wait waits for a created Kafka cluster
+	// to be listed, the task returned by create cannot be polled
+	kafkaAppearTimeout = 5 * time.Minute
+)
+
+var (
+	waitEnabled  bool
+	waitTimeout  time.Duration
+	pollInterval time.Duration
+
+	// waitCmds are the commands which wait, only the flags of the running
+	// one are parsed
+	waitCmds []*cobra.Command
+)
+
+// addWaitFlags adds --wait and --poll-interval to a command. The global
+// --timeout limits the wait as well as each API request.
+func addWaitFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&waitEnabled, "wait", false, "Wait until the operation completes, at most --timeout, default: 30m")
+	addPollFlags(cmd, "Interval between the status checks with --wait")
+}
+
+// addPollFlags adds --poll-interval and the hidden --wait-timeout, the former
+// flag of the timeout of the wait, kept for the commands where --timeout has
+// another meaning
+func addPollFlags(cmd *cobra.Command, usage string) {
+	cmd.Flags().DurationVar(&waitTimeout, "wait-timeout", defaultWaitTimeout, "Maximum time to wait")
+	_ = cmd.Flags().MarkHidden("wait-timeout")
+	cmd.Flags().DurationVar(&pollInterval, "poll-interval", defaultPollInterval, usage)
+	waitCmds = append(waitCmds, cmd)
+}
+
+// currentWaitTimeout returns the maximum time of a wait: --wait-timeout when
+// it is given, else the global --timeout when it is given, else 30m. 0 is no
+// limit.
+func currentWaitTimeout() time.Duration {
+	for _, cmd := range waitCmds {
+		if cmd.Flags().Changed("wait-timeout") {
+			return waitTimeout
+		}
+	}
+	if rootCmd.PersistentFlags().Changed("timeout") {
+		return requestTimeout
+	}
+	return waitTimeout
+}
+
+// waitCheck returns the current state of a resource and whether it is final
+type waitCheck func(ctx context.Context) (state string, done bool, err error)
+
+// waitFor polls the resource when --wait is set, without --dry-run as
+// nothing was changed
+func waitFor(ctx context.Context, resource string, check waitCheck) error {
+	if !waitEnabled || dryRun {
+		return nil
+	}
+	return poll(ctx, resource, check)
+}
+
+// poll calls check until it reports a final state, an error or the
+// timeout of the wait is reached
+func poll(ctx context.Context, resource string, check waitCheck) error {
+	if pollInterval <= 0 {
+		return usageError("--poll-interval must be greater than 0")
+	}
+	timeout := currentWaitTimeout()
+	deadline := time.Now().Add(timeout)
+	lastState := ""
+	for {
+		state, done, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		if state != lastState {
+			logging.Infof("%s: %s", resource, state)
+			lastState = state
+		}
+		if done {
+			return nil
+		}
+		if timeout > 0 && time.Now().Add(pollInterval).After(deadline) {
+			return &cmdError{code: ExitTimeout, err: fmt.Errorf("timed out after %v waiting for %s, last state: %s", timeout, resource, state)}
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// isErrorStatus reports whether a status of a resource is an error state
+func isErrorStatus(status string) bool {
+	s := strings.ToLower(status)
+	return strings.Contains(s, "error") || strings.Contains(s, "fail")
+}
+
+// waitForStatus waits until the status returned by get is one of targets
+func waitForStatus(ctx context.Context, resource string, get func(ctx context.Context) (string, error), targets ...string) error {
+	return waitFor(ctx, resource, func(ctx context.Context) (string, bool, error) {
+		status, err := get(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		if isErrorStatus(status) {
+			return status, true, fmt.Errorf("%s is in the error state %s", resource, status)
+		}
+		return status, isStatus(status, targets), nil
+	})
+}
+
+// isStatus reports whether status is one of targets
+func isStatus(status string, targets []string) bool {
+	for _, target := range targets {
+		if strings.EqualFold(status, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateCheck returns the status of a resource and whether it shows an
+// update, e.g. its new size
+type updateCheck func(ctx context.Context) (status string, updated bool, err error)
+
+// waitForUpdate waits for an update which returns no task. The resource is
+// still in one of targets right after the request, so the update is started
+// once the resource shows it or its status leaves targets, and finished when
+// its status is one of targets again.
+func waitForUpdate(ctx context.Context, resource string, get updateCheck, targets ...string) error {
+	started := false
+	return waitFor(ctx, resource, func(ctx context.Context) (string, bool, error) {
+		status, updated, err := get(ctx)
+		if err != nil {
+			return "", false, err
+		}
+		if isErrorStatus(status) {
+			return status, true, fmt.Errorf("%s is in the error state %s", resource, status)
+		}
+		ready := isStatus(status, targets)
+		if updated || !ready {
+			started = true
+		}
+		if !started {
+			return "update pending", false, nil
+		}
+		return status, ready, nil
+	})
+}
+
+// waitForDeletion waits until get reports that the resource is not found
+func waitForDeletion(ctx context.Context, resource string, get func(ctx context.Context) (string, error)) error {
+	return waitFor(ctx, resource, func(ctx context.Context) (string, bool, error) {
+		status, err := get(ctx)
+		if errors.Is(err, gobizfly.ErrNotFound) {
+			return "deleted", true, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if isErrorStatus(status) {
+			return status, true, fmt.Errorf("%s is in the error state %s", resource, status)
+		}
+		return status, false, nil
+	})
+}
+
+// waitForTask waits until a task of the server API is finished
+func waitForTask(ctx context.Context, client *gobizfly.Client, taskID string) error {
+	return waitFor(ctx, "task "+taskID, taskCheck(client, taskID, nil))
+}
+
+// taskCheck polls a task, last is set to the task of the latest check
+func taskCheck(client *gobizfly.Client, taskID string, last **gobizfly.Task) waitCheck {
+	return func(ctx context.Context) (string, bool, error) {
+		task, err := client.CloudServer.GetTask(ctx, taskID)
+		if err != nil {
+			return "", false, err
+		}
+		if last != nil {
+			*last = task
+		}
+		state := taskState(task)
+		if task.Ready && !task.Result.Success {
+			return state, true, fmt.Errorf("task %s failed", taskID)
+		}
+		return state, task.Ready, nil
+	}
+}
+
+// taskState describes the progress of a task
+func taskState(task *gobizfly.Task) string {
+	switch {
+	case !task.Ready:
+		return "in progress " + strconv.Itoa(task.Result.Progress) + "%"
+	case task.Result.Success:
+		return "succeeded"
+	default:
+		return "failed"
+	}
+}
+
+func serverStatus(client *gobizfly.Client, serverID string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		server, err := client.CloudServer.Get(ctx, serverID)
+		if err != nil {
+			return "", err
+		}
+		return server.Status, nil
+	}
+}
+
+// volumeSizeUpdate reports that a volume is extended once it has size GB
+func volumeSizeUpdate(client *gobizfly.Client, volumeID string, size int) updateCheck {
+	return func(ctx context.Context) (string, bool, error) {
+		volume, err := client.CloudServer.Volumes().Get(ctx, volumeID)
+		if err != nil {
+			return "", false, err
+		}
+		return volume.Status, volume.Size >= size, nil
+	}
+}
+
+func volumeStatus(client *gobizfly.Client, volumeID string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		volume, err := client.CloudServer.Volumes().Get(ctx, volumeID)
+		if err != nil {
+			return "", err
+		}
+		return volume.Status, nil
+	}
+}
+
+func clusterStatus(client *gobizfly.Client, clusterID string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		cluster, err := client.KubernetesEngine.Get(ctx, clusterID)
+		if err != nil {
+			return "", err
+		}
+		return cluster.ClusterStatus, nil
+	}
+}
+
+// workerPoolSizeUpdate reports that a worker pool is updated once it has
+// the desired size
+func workerPoolSizeUpdate(client *gobizfly.Client, clusterID, poolID string, desired int) updateCheck {
+	return func(ctx context.Context) (string, bool, error) {
+		pool, err := client.KubernetesEngine.GetClusterWorkerPool(ctx, clusterID, poolID)
+		if err != nil {
+			return "", false, err
+		}
+		return pool.ProvisionStatus, pool.DesiredSize == desired, nil
+	}
+}
+
+// loadBalancerTypeUpdate reports that a load balancer is resized once it
+// has the type lbType
+func loadBalancerTypeUpdate(client *gobizfly.Client, lbID, lbType string) updateCheck {
+	return func(ctx context.Context) (string, bool, error) {
+		lb, err := client.CloudLoadBalancer.Get(ctx, lbID)
+		if err != nil {
+			return "", false, err
+		}
+		return lb.ProvisioningStatus, strings.EqualFold(lb.Type, lbType), nil
+	}
+}
+
+func loadBalancerStatus(client *gobizfly.Client, lbID string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		lb, err := client.CloudLoadBalancer.Get(ctx, lbID)
+		if err != nil {
+			return "", err
+		}
+		return lb.ProvisioningStatus, nil
+	}
+}
+
+func kafkaStatus(client *gobizfly.Client, clusterID string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		cluster, err := client.Kafka.Get(ctx, clusterID)
+		if err != nil {
+			return "", err
+		}
+		return cluster.Status, nil
+	}
+}
+
+// kafkaResizeUpdate reports that a Kafka cluster is resized once it has the
+// flavor or all its nodes have the volume size of req
+func kafkaResizeUpdate(client *gobizfly.Client, clusterID string, req *gobizfly.KafkaResizeClusterRequest) updateCheck {
+	return func(ctx context.Context) (string, bool, error) {
+		cluster, err := client.Kafka.Get(ctx, clusterID)
+		if err != nil {
+			return "", false, err
+		}
+		if req.Type == "flavor" {
+			return cluster.Status, cluster.Flavor == req.Flavor, nil
+		}
+		updated := len(cluster.Nodes) > 0
+		for _, node := range cluster.Nodes {
+			updated = updated && node.VolumeSize >= req.VolumeSize
+		}
+		return cluster.Status, updated, nil
+	}
+}
+
+// kafkaClusterIDs returns the IDs of the Kafka clusters named name
+func kafkaClusterIDs(ctx context.Context, client *gobizfly.Client, name string) (map[string]bool, error) {
+	clusters, err := client.Kafka.List(ctx, &gobizfly.KafkaClusterListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]bool)
+	for _, cluster := range clusters {
+		if cluster.Name == name {
+			ids[cluster.ID] = true
+		}
+	}
+	return ids, nil
+}
+
+// kafkaCreatedStatus is used after create which only returns a task ID. The
+// created cluster is the one named name which is not in existing, the
+// clusters of that name before the create.
+func kafkaCreatedStatus(client *gobizfly.Client, name string, existing map[string]bool) func(ctx context.Context) (string, error) {
+	start := time.Now()
+	return func(ctx context.Context) (string, error) {
+		ids, err := kafkaClusterIDs(ctx, client, name)
+		if err != nil {
+			return "", err
+		}
+		var created []string
+		for id := range ids {
+			if !existing[id] {
+				created = append(created, id)
+			}
+		}
+		switch {
+		case len(created) > 1:
+			sort.Strings(created)
+			return "", fmt.Errorf("several new Kafka clusters are named %s: %s, use bizfly kafka clusters get <cluster-id>",
+				name, strings.Join(created, ", "))
+		case len(created) == 0 && time.Since(start) > kafkaAppearTimeout:
+			return "", fmt.Errorf("Kafka cluster %s is not listed %v after its creation, the creation may have failed", name, kafkaAppearTimeout)
+		case len(created) == 0:
+			return "pending", nil
+		}
+		return kafkaStatus(client, created[0])(ctx)
+	}
+}
+
+// Final states of the resources
+var (
+	serverActiveStatus      = []string{"ACTIVE"}
+	serverStoppedStatus     = []string{"SHUTOFF"}
+	volumeReadyStatus       = []string{"available", "in-use"}
+	volumeAttachedStatus    = []string{"in-use"}
+	clusterReadyStatus      = []string{"PROVISIONED"}
+	workerPoolReadyStatus   = []string{"PROVISIONED"}
+	loadBalancerReadyStatus = []string{"ACTIVE"}
+	kafkaClusterReadyStatus = []string{"ACTIVE", "RUNNING"}
+)
@@ -0,0 +1,72 @@
+/*
+Copyright © (2020-2021) Bizfly Cloud
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type updateState struct {
+	status  string
+	updated bool
+}
+
+func TestWaitForUpdate(t *testing.T) {
+	defer func(enabled bool, timeout, interval time.Duration) {
+		waitEnabled, waitTimeout, pollInterval = enabled, timeout, interval
+	}(waitEnabled, waitTimeout, pollInterval)
+	waitEnabled, waitTimeout, pollInterval = true, 50*time.Millisecond, time.Millisecond
+
+	tests := []struct {
+		name   string
+		states []updateState
+		checks int
+		code   int
+	}{
+		{name: "started by the status", states: []updateState{{"ACTIVE", false}, {"ACTIVE", false}, {"RESIZE", false}, {"ACTIVE", false}}, checks: 4},
+		{name: "already updated", states: []updateState{{"ACTIVE", true}}, checks: 1},
+		{name: "updated between checks", states: []updateState{{"ACTIVE", false}, {"ACTIVE", true}}, checks: 2},
+		{name: "never started", states: []updateState{{"ACTIVE", false}}, code: ExitTimeout},
+		{name: "error", states: []updateState{{"ACTIVE", false}, {"ERROR", false}}, checks: 2, code: ExitError},
+	}
+	for _, tc := range tests {
+		checks := 0
+		get := func(ctx context.Context) (string, bool, error) {
+			state := tc.states[len(tc.states)-1]
+			if checks < len(tc.states) {
+				state = tc.states[checks]
+			}
+			checks++
+			return state.status, state.updated, nil
+		}
+		err := waitForUpdate(context.Background(), "Server web-1", get, serverActiveStatus...)
+		var ce *cmdError
+		switch {
+		case tc.code == 0 && err != nil:
+			t.Errorf("%s: %v", tc.name, err)
+		case tc.code == ExitTimeout && !(errors.As(err, &ce) && ce.code == ExitTimeout):
+			t.Errorf("%s: got %v, want a timeout", tc.name, err)
+		case tc.code == ExitError && err == nil:
+			t.Errorf("%s: got no error", tc.name)
+		case tc.code != ExitTimeout && checks != tc.checks:
+			t.Errorf("%s: %d checks, want %d", tc.name, checks, tc.checks)
+		}
+	}
+}
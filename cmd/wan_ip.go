@@ -17,11 +17,10 @@ limitations under the License.
 package cmd
 
 import (
-	"fmt"
-	"log"
 	"strconv"
 
 	"github.com/bizflycloud/bizflyctl/formatter"
+	"github.com/bizflycloud/bizflyctl/logging"
 	"github.com/bizflycloud/gobizfly"
 	"github.com/spf13/cobra"
 )
@@ -37,18 +36,21 @@ var wanIPCmd = &cobra.Command{
 	Short: "Bizfly Cloud WAN IP Interaction",
 	Long:  `Bizfly Cloud WAN IP Interaction: Create, Delete, List, Get, Action`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("WAN IP called")
+		logging.Debugf("WAN IP called")
 	},
 }
 
 var wanIpListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List WAN IP",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		wanIps, err := client.CloudServer.PublicNetworkInterfaces().List(ctx)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		for _, wanIp := range wanIps {
@@ -66,24 +68,32 @@ var wanIpListCmd = &cobra.Command{
 				wanIp.UpdatedAt,
 			})
 		}
-		formatter.Output(wanIPHeader, data)
+		return formatter.Output(wanIPHeader, data, wanIps)
 	},
 }
 
 var wanIPCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create WAN IP",
-	Run: func(cmd *cobra.Command, args []string) {
-		client, ctx := getApiClient(cmd)
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
 		payload := gobizfly.CreatePublicNetworkInterfacePayload{
 			Name:             wanIpName,
 			AvailabilityZone: availabilityZone,
 			AttachedServer:   serverID,
 		}
+		if serverID != "" {
+			if payload.AttachedServer, err = resolveServer(ctx, client, serverID); err != nil {
+				return err
+			}
+		}
 
 		wanIp, err := client.CloudServer.PublicNetworkInterfaces().Create(ctx, &payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -99,7 +109,7 @@ var wanIPCreateCmd = &cobra.Command{
 			wanIp.CreatedAt,
 			wanIp.UpdatedAt,
 		})
-		formatter.Output(wanIPHeader, data)
+		return formatter.Output(wanIPHeader, data, wanIp)
 	},
 }
 
@@ -107,14 +117,21 @@ var wanIPGetCmd = &cobra.Command{
 	Use:   "get",
 	Short: "Get WAN IP",
 	Long:  `Get WAN IP: ./bizfly wan-ip get <wan-ip-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
 		}
-		client, ctx := getApiClient(cmd)
-		wanIp, err := client.CloudServer.PublicNetworkInterfaces().Get(ctx, args[0])
+		client, ctx, err := getApiClient(cmd)
 		if err != nil {
-			log.Fatal(err)
+			return err
+		}
+		wanIPID, err := resolveWanIP(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		wanIp, err := client.CloudServer.PublicNetworkInterfaces().Get(ctx, wanIPID)
+		if err != nil {
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -130,7 +147,7 @@ var wanIPGetCmd = &cobra.Command{
 			wanIp.CreatedAt,
 			wanIp.UpdatedAt,
 		})
-		formatter.Output(wanIPHeader, data)
+		return formatter.Output(wanIPHeader, data, wanIp)
 	},
 }
 
@@ -138,16 +155,24 @@ var wanIpDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete WAN IP",
 	Long:  `Delete WAN IP: ./bizfly wan-ip delete <wan-ip-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		wanIPID, err := resolveWanIP(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
-		err := client.CloudServer.PublicNetworkInterfaces().Delete(ctx, args[0])
+		err = client.CloudServer.PublicNetworkInterfaces().Delete(ctx, wanIPID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		fmt.Println("The WAN IP is deleted")
+		logging.Infof("The WAN IP is deleted")
+		return nil
 	},
 }
 
@@ -155,22 +180,33 @@ var wanIpAttachServerCmd = &cobra.Command{
 	Use:   "attach-server",
 	Short: "Attach WAN IP to server",
 	Long:  `Attach WAN IP to server: ./bizfly wan-ip attach-server <wan-ip-id> --server-id <server-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		wanIPID, err := resolveWanIP(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+		attachServerID, err := resolveServer(ctx, client, serverID)
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		payload := gobizfly.ActionPublicNetworkInterfacePayload{
 			Action:   "attach_server",
-			ServerID: serverID,
+			ServerID: attachServerID,
 		}
-		err := client.CloudServer.PublicNetworkInterfaces().Action(ctx, args[0], &payload)
+		err = client.CloudServer.PublicNetworkInterfaces().Action(ctx, wanIPID, &payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		wanIp, err := client.CloudServer.PublicNetworkInterfaces().Get(ctx, args[0])
+		wanIp, err := client.CloudServer.PublicNetworkInterfaces().Get(ctx, wanIPID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -186,7 +222,7 @@ var wanIpAttachServerCmd = &cobra.Command{
 			wanIp.CreatedAt,
 			wanIp.UpdatedAt,
 		})
-		formatter.Output(wanIPHeader, data)
+		return formatter.Output(wanIPHeader, data, wanIp)
 	},
 }
 
@@ -194,21 +230,28 @@ var wanIpDetachServerCmd = &cobra.Command{
 	Use:   "detach-server",
 	Short: "Detach the WAN IP from server",
 	Long:  `Detach the WAN IP from server: ./bizfly wan-ip detach-server <wan-ip-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		wanIPID, err := resolveWanIP(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		payload := gobizfly.ActionPublicNetworkInterfacePayload{
 			Action: "detach_server",
 		}
-		err := client.CloudServer.PublicNetworkInterfaces().Action(ctx, args[0], &payload)
+		err = client.CloudServer.PublicNetworkInterfaces().Action(ctx, wanIPID, &payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		wanIp, err := client.CloudServer.PublicNetworkInterfaces().Get(ctx, args[0])
+		wanIp, err := client.CloudServer.PublicNetworkInterfaces().Get(ctx, wanIPID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -224,7 +267,7 @@ var wanIpDetachServerCmd = &cobra.Command{
 			wanIp.CreatedAt,
 			wanIp.UpdatedAt,
 		})
-		formatter.Output(wanIPHeader, data)
+		return formatter.Output(wanIPHeader, data, wanIp)
 	},
 }
 
@@ -232,21 +275,28 @@ var wanIpConvertToPaidCmd = &cobra.Command{
 	Use:   "convert-to-paid",
 	Short: "Convert WAN IP to paid one",
 	Long:  `Convert WAN IP to paid one: ./bizfly wan-ip convert-to-paid <wan-ip-id>`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if len(args) != 1 {
-			log.Fatal("Invalid argument")
+			return usageError("Invalid argument")
+		}
+		client, ctx, err := getApiClient(cmd)
+		if err != nil {
+			return err
+		}
+		wanIPID, err := resolveWanIP(ctx, client, args[0])
+		if err != nil {
+			return err
 		}
-		client, ctx := getApiClient(cmd)
 		payload := gobizfly.ActionPublicNetworkInterfacePayload{
 			Action: "convert_to_paid",
 		}
-		err := client.CloudServer.PublicNetworkInterfaces().Action(ctx, args[0], &payload)
+		err = client.CloudServer.PublicNetworkInterfaces().Action(ctx, wanIPID, &payload)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-		wanIp, err := client.CloudServer.PublicNetworkInterfaces().Get(ctx, args[0])
+		wanIp, err := client.CloudServer.PublicNetworkInterfaces().Get(ctx, wanIPID)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		var data [][]string
 		data = append(data, []string{
@@ -262,7 +312,7 @@ var wanIpConvertToPaidCmd = &cobra.Command{
 			wanIp.CreatedAt,
 			wanIp.UpdatedAt,
 		})
-		formatter.Output(wanIPHeader, data)
+		return formatter.Output(wanIPHeader, data, wanIp)
 	},
 }
 
@@ -6,6 +6,12 @@ const (
 	VcHaNoiRegion   = "VC-HaNoi"
 )
 
+// AvailabilityZones are the availability zones of the servers of the regions
+var AvailabilityZones = map[string][]string{
+	HaNoiRegion:     {"HN1", "HN2"},
+	HoChiMinhRegion: {"HCM1"},
+}
+
 var RegionMapping = map[string]string{
 	"hn":        HaNoiRegion,
 	"hanoi":     HaNoiRegion,
@@ -1,15 +1,124 @@
 package formatter
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/jedib0t/go-pretty/table"
 	"github.com/olekukonko/tablewriter"
 )
 
-// Output is func support string data
-func Output(header []string, data [][]string) {
-	table := tablewriter.NewWriter(os.Stdout)
+const (
+	// TableFormat is the default borderless table
+	TableFormat = "table"
+	// WideFormat is the table format with additional columns
+	WideFormat = "wide"
+	// JSONFormat serializes the underlying API object to JSON
+	JSONFormat = "json"
+	// YAMLFormat serializes the underlying API object to YAML
+	YAMLFormat = "yaml"
+	// CSVFormat prints the table columns as comma separated values
+	CSVFormat = "csv"
+)
+
+// SupportedFormats lists the values accepted by SetFormat
+var SupportedFormats = []string{TableFormat, WideFormat, JSONFormat, YAMLFormat, CSVFormat}
+
+var (
+	outputFormat           = TableFormat
+	out          io.Writer = os.Stdout
+)
+
+// SetFormat selects the output format used by Output and SimpleOutput.
+// Template formats take their template after "=", e.g. "jsonpath={.id}".
+func SetFormat(format string) error {
+	if format == "" {
+		format = TableFormat
+	}
+	name, arg := format, ""
+	if i := strings.Index(format, "="); i >= 0 {
+		name, arg = format[:i], format[i+1:]
+	}
+	name = strings.ToLower(name)
+	if isTemplateFormat(name) {
+		return setTemplateFormat(name, arg)
+	}
+	for _, f := range SupportedFormats {
+		if f == name && arg == "" {
+			outputFormat = name
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output format %q, must be one of: %s", format,
+		strings.Join(append(append([]string{}, SupportedFormats...), TemplateFormats...), ", "))
+}
+
+// Format returns the selected output format
+func Format() string {
+	return outputFormat
+}
+
+// IsWide reports whether the extra table columns should be printed
+func IsWide() bool {
+	return outputFormat == WideFormat
+}
+
+// IsStructured reports whether the output is a serialized document rather than a table
+func IsStructured() bool {
+	switch outputFormat {
+	case JSONFormat, YAMLFormat, JSONPathFormat, GoTemplateFormat:
+		return true
+	}
+	return false
+}
+
+// SetOutput sets the writer used by Output and SimpleOutput
+func SetOutput(w io.Writer) {
+	out = w
+}
+
+// Output is func support string data. obj is the API object the rows were
+// built from, it is serialized or rendered by the template instead of the
+// rows for structured formats. The error is the one of the encoding or of
+// the template.
+func Output(header []string, data [][]string, obj interface{}) error {
+	switch outputFormat {
+	case JSONFormat, YAMLFormat, JSONPathFormat, GoTemplateFormat:
+		if obj == nil {
+			obj = rowsToMaps(header, data)
+		}
+		if outputFormat == JSONPathFormat || outputFormat == GoTemplateFormat {
+			return writeTemplate(obj)
+		}
+		return writeStructured(obj)
+	case CSVFormat:
+		return writeCSV(header, data)
+	default:
+		writeTable(header, data)
+		return nil
+	}
+}
+
+// SimpleOutput is func support any type of data. obj is the API object the
+// rows were built from, it is serialized instead of the rows for structured formats.
+func SimpleOutput(header table.Row, rows []table.Row, obj interface{}) error {
+	switch outputFormat {
+	case TableFormat, WideFormat:
+		t := table.NewWriter()
+		t.SetOutputMirror(out)
+		t.AppendHeader(header)
+		t.AppendRows(rows)
+		t.Render()
+		return nil
+	default:
+		return Output(rowToStrings(header), rowsToStrings(rows), obj)
+	}
+}
+
+func writeTable(header []string, data [][]string) {
+	table := tablewriter.NewWriter(out)
 	table.SetHeader(header)
 	table.SetAutoWrapText(false)
 	table.SetAutoFormatHeaders(true)
@@ -26,11 +135,33 @@ func Output(header []string, data [][]string) {
 	table.Render()
 }
 
-// SimpleOutput is func support any type of data
-func SimpleOutput(header table.Row, rows []table.Row) {
-	t := table.NewWriter()
-	t.SetOutputMirror(os.Stdout)
-	t.AppendHeader(header)
-	t.AppendRows(rows)
-	t.Render()
+func rowToStrings(row table.Row) []string {
+	result := make([]string, 0, len(row))
+	for _, v := range row {
+		result = append(result, fmt.Sprint(v))
+	}
+	return result
+}
+
+func rowsToStrings(rows []table.Row) [][]string {
+	result := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, rowToStrings(row))
+	}
+	return result
+}
+
+// rowsToMaps is used when a command has no API object to serialize
+func rowsToMaps(header []string, data [][]string) []map[string]string {
+	result := make([]map[string]string, 0, len(data))
+	for _, row := range data {
+		item := make(map[string]string, len(header))
+		for i, h := range header {
+			if i < len(row) {
+				item[h] = row[i]
+			}
+		}
+		result = append(result, item)
+	}
+	return result
 }
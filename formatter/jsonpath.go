@@ -0,0 +1,543 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// jsonPath is a parsed JSONPath template such as "{.items[*].id}". Text
+// outside of the braces is printed as is. Like kubectl, it supports
+// {range <path>}...{end} and quoted literals such as {"\n"}.
+type jsonPath struct {
+	nodes []jsonPathNode
+}
+
+type nodeKind int
+
+const (
+	textNode nodeKind = iota
+	pathNode
+	rangeNode
+)
+
+// jsonPathNode is a text, a path printing its values, or a range executing
+// its body for each value of its path
+type jsonPathNode struct {
+	kind     nodeKind
+	text     string
+	segments []pathSegment
+	// root tells the path starts at the document, "$", rather than at the
+	// current element of a range
+	root bool
+	body []jsonPathNode
+}
+
+type segmentKind int
+
+const (
+	fieldSegment segmentKind = iota
+	recursiveSegment
+	indexSegment
+	sliceSegment
+	wildcardSegment
+	filterSegment
+)
+
+type pathSegment struct {
+	kind  segmentKind
+	name  string
+	index int
+	// slice bounds, hasStart/hasEnd tell whether they were given
+	start, end       int
+	hasStart, hasEnd bool
+	// filter is "[?(@.path op value)]"
+	filterPath  []pathSegment
+	filterOp    string
+	filterValue interface{}
+}
+
+func parseJSONPath(tmpl string) (*jsonPath, error) {
+	nodes, _, err := parseNodes(tmpl, false)
+	if err != nil {
+		return nil, err
+	}
+	return &jsonPath{nodes: nodes}, nil
+}
+
+// parseNodes parses tmpl until its end, or until {end} in a range. It returns
+// the rest of tmpl after the {end}.
+func parseNodes(tmpl string, inRange bool) ([]jsonPathNode, string, error) {
+	var nodes []jsonPathNode
+	for len(tmpl) > 0 {
+		open := strings.Index(tmpl, "{")
+		if open < 0 {
+			nodes = append(nodes, jsonPathNode{kind: textNode, text: tmpl})
+			break
+		}
+		if open > 0 {
+			nodes = append(nodes, jsonPathNode{kind: textNode, text: tmpl[:open]})
+		}
+		end := closingBrace(tmpl[open:])
+		if end < 0 {
+			return nil, "", fmt.Errorf("unclosed expression in jsonpath %q", tmpl)
+		}
+		expr := strings.TrimSpace(tmpl[open+1 : open+end])
+		tmpl = tmpl[open+end+1:]
+		switch {
+		case expr == "end":
+			if !inRange {
+				return nil, "", fmt.Errorf("{end} without {range} in jsonpath")
+			}
+			return nodes, tmpl, nil
+		case expr == "range" || strings.HasPrefix(expr, "range "):
+			root, segments, err := parsePathExpr(strings.TrimPrefix(expr, "range"))
+			if err != nil {
+				return nil, "", err
+			}
+			if len(segments) == 0 {
+				return nil, "", fmt.Errorf("{range} requires a path in jsonpath, e.g. {range .items[*]}")
+			}
+			body, rest, err := parseNodes(tmpl, true)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{kind: rangeNode, segments: segments, root: root, body: body})
+			tmpl = rest
+		case len(expr) > 0 && (expr[0] == '"' || expr[0] == '\''):
+			text, err := parseQuoted(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{kind: textNode, text: text})
+		default:
+			root, segments, err := parsePathExpr(expr)
+			if err != nil {
+				return nil, "", err
+			}
+			nodes = append(nodes, jsonPathNode{kind: pathNode, segments: segments, root: root})
+		}
+	}
+	if inRange {
+		return nil, "", fmt.Errorf("{range} without {end} in jsonpath")
+	}
+	return nodes, "", nil
+}
+
+// closingBrace returns the index of the "}" closing the "{" at the start of
+// s, skipping the quoted literals
+func closingBrace(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseQuoted returns the text of a literal, with the escapes of Go strings
+// in double quotes, e.g. {"\n"} or {'\t'}
+func parseQuoted(expr string) (string, error) {
+	if len(expr) < 2 || expr[len(expr)-1] != expr[0] {
+		return "", fmt.Errorf("unterminated literal %s in jsonpath", expr)
+	}
+	if expr[0] == '\'' {
+		return expr[1 : len(expr)-1], nil
+	}
+	text, err := strconv.Unquote(expr)
+	if err != nil {
+		return "", fmt.Errorf("invalid literal %s in jsonpath", expr)
+	}
+	return text, nil
+}
+
+// parsePathExpr parses a path such as ".items[0].id". A path starting with
+// "$" is relative to the document, other paths to the current element.
+func parsePathExpr(expr string) (bool, []pathSegment, error) {
+	expr = strings.TrimSpace(expr)
+	root := strings.HasPrefix(expr, "$")
+	if root {
+		expr = expr[1:]
+	} else {
+		expr = strings.TrimPrefix(expr, "@")
+	}
+	var segments []pathSegment
+	for len(expr) > 0 {
+		switch {
+		case strings.HasPrefix(expr, ".."):
+			name, rest := readName(expr[2:])
+			if name == "" {
+				return false, nil, fmt.Errorf("missing field name after '..' in jsonpath")
+			}
+			if !validName(name) {
+				return false, nil, fmt.Errorf("unsupported %q in jsonpath", expr)
+			}
+			segments = append(segments, pathSegment{kind: recursiveSegment, name: name})
+			expr = rest
+		case expr[0] == '.':
+			name, rest := readName(expr[1:])
+			switch {
+			case name == "":
+				// a lone "." refers to the current object
+			case name == "*":
+				segments = append(segments, pathSegment{kind: wildcardSegment})
+			case !validName(name):
+				return false, nil, fmt.Errorf("unsupported %q in jsonpath", expr)
+			default:
+				segments = append(segments, pathSegment{kind: fieldSegment, name: name})
+			}
+			expr = rest
+		case expr[0] == '[':
+			end := matchingBracket(expr)
+			if end < 0 {
+				return false, nil, fmt.Errorf("unclosed '[' in jsonpath")
+			}
+			segment, err := parseBracket(expr[1:end])
+			if err != nil {
+				return false, nil, err
+			}
+			segments = append(segments, segment)
+			expr = expr[end+1:]
+		default:
+			name, rest := readName(expr)
+			if name == "" || !validName(name) {
+				return false, nil, fmt.Errorf("unsupported %q in jsonpath", expr)
+			}
+			segments = append(segments, pathSegment{kind: fieldSegment, name: name})
+			expr = rest
+		}
+	}
+	return root, segments, nil
+}
+
+// validName reports whether name is a field name. Other characters are
+// syntax which is not supported, such as functions or operators.
+func validName(name string) bool {
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+func readName(s string) (string, string) {
+	i := 0
+	for i < len(s) && s[i] != '.' && s[i] != '[' {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func matchingBracket(s string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func parseBracket(s string) (pathSegment, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case s == "*":
+		return pathSegment{kind: wildcardSegment}, nil
+	case strings.HasPrefix(s, "?(") && strings.HasSuffix(s, ")"):
+		return parseFilter(s[2 : len(s)-1])
+	case len(s) > 1 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]:
+		return pathSegment{kind: fieldSegment, name: s[1 : len(s)-1]}, nil
+	case strings.Contains(s, ":"):
+		bounds := strings.SplitN(s, ":", 2)
+		seg := pathSegment{kind: sliceSegment}
+		var err error
+		if b := strings.TrimSpace(bounds[0]); b != "" {
+			if seg.start, err = strconv.Atoi(b); err != nil {
+				return seg, fmt.Errorf("invalid slice start %q in jsonpath", b)
+			}
+			seg.hasStart = true
+		}
+		if b := strings.TrimSpace(bounds[1]); b != "" {
+			if seg.end, err = strconv.Atoi(b); err != nil {
+				return seg, fmt.Errorf("invalid slice end %q in jsonpath", b)
+			}
+			seg.hasEnd = true
+		}
+		return seg, nil
+	default:
+		i, err := strconv.Atoi(s)
+		if err != nil {
+			return pathSegment{}, fmt.Errorf("invalid index %q in jsonpath", s)
+		}
+		return pathSegment{kind: indexSegment, index: i}, nil
+	}
+}
+
+func parseFilter(s string) (pathSegment, error) {
+	seg := pathSegment{kind: filterSegment}
+	for _, op := range []string{"<", ">", "=~", "&&", "||"} {
+		if strings.Contains(s, op) {
+			return seg, fmt.Errorf("unsupported filter %q in jsonpath, only == and != are supported", s)
+		}
+	}
+	for _, op := range []string{"==", "!="} {
+		if i := strings.Index(s, op); i >= 0 {
+			left := strings.TrimSpace(s[:i])
+			right := strings.TrimSpace(s[i+len(op):])
+			if !strings.HasPrefix(left, "@") {
+				return seg, fmt.Errorf("filter must start with '@' in jsonpath: %q", s)
+			}
+			_, path, err := parsePathExpr(left[1:])
+			if err != nil {
+				return seg, err
+			}
+			seg.filterPath = path
+			seg.filterOp = op
+			seg.filterValue = parseLiteral(right)
+			return seg, nil
+		}
+	}
+	// "[?(@.field)]" keeps the elements having the field
+	if !strings.HasPrefix(strings.TrimSpace(s), "@") {
+		return seg, fmt.Errorf("filter must start with '@' in jsonpath: %q", s)
+	}
+	_, path, err := parsePathExpr(strings.TrimSpace(s)[1:])
+	if err != nil {
+		return seg, err
+	}
+	seg.filterPath = path
+	return seg, nil
+}
+
+func parseLiteral(s string) interface{} {
+	if len(s) > 1 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	return json.Number(s)
+}
+
+// execute evaluates the template against a document decoded from JSON
+func (jp *jsonPath) execute(doc interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := executeNodes(&buf, jp.nodes, doc, doc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func executeNodes(buf *bytes.Buffer, nodes []jsonPathNode, doc, current interface{}) error {
+	for _, node := range nodes {
+		start := current
+		if node.root {
+			start = doc
+		}
+		switch node.kind {
+		case textNode:
+			buf.WriteString(node.text)
+		case rangeNode:
+			for _, item := range rangeItems(evalPath([]interface{}{start}, node.segments), node.segments) {
+				if err := executeNodes(buf, node.body, doc, item); err != nil {
+					return err
+				}
+			}
+		default:
+			values := evalPath([]interface{}{start}, node.segments)
+			strs := make([]string, 0, len(values))
+			for _, v := range values {
+				s, err := valueString(v)
+				if err != nil {
+					return err
+				}
+				strs = append(strs, s)
+			}
+			buf.WriteString(strings.Join(strs, " "))
+		}
+	}
+	return nil
+}
+
+// rangeItems returns the elements a range iterates on. A path ending with a
+// field, e.g. {range .items}, iterates on the elements of the list.
+func rangeItems(values []interface{}, segments []pathSegment) []interface{} {
+	last := segments[len(segments)-1].kind
+	if len(values) != 1 || (last != fieldSegment && last != recursiveSegment) {
+		return values
+	}
+	if items, ok := values[0].([]interface{}); ok {
+		return items
+	}
+	return values
+}
+
+func evalPath(nodes []interface{}, segments []pathSegment) []interface{} {
+	for _, seg := range segments {
+		var next []interface{}
+		for _, node := range nodes {
+			next = append(next, evalSegment(node, seg)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+func evalSegment(node interface{}, seg pathSegment) []interface{} {
+	switch seg.kind {
+	case fieldSegment:
+		if m, ok := node.(map[string]interface{}); ok {
+			if v, ok := m[seg.name]; ok {
+				return []interface{}{v}
+			}
+		}
+	case recursiveSegment:
+		return findRecursive(node, seg.name)
+	case wildcardSegment:
+		switch n := node.(type) {
+		case []interface{}:
+			return n
+		case map[string]interface{}:
+			result := make([]interface{}, 0, len(n))
+			for _, k := range sortedKeys(n) {
+				result = append(result, n[k])
+			}
+			return result
+		}
+	case indexSegment:
+		if arr, ok := node.([]interface{}); ok {
+			i := seg.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				return []interface{}{arr[i]}
+			}
+		}
+	case sliceSegment:
+		if arr, ok := node.([]interface{}); ok {
+			start, end := 0, len(arr)
+			if seg.hasStart {
+				start = clampIndex(seg.start, len(arr))
+			}
+			if seg.hasEnd {
+				end = clampIndex(seg.end, len(arr))
+			}
+			if start < end {
+				return arr[start:end]
+			}
+		}
+	case filterSegment:
+		arr, ok := node.([]interface{})
+		if !ok {
+			return nil
+		}
+		var result []interface{}
+		for _, item := range arr {
+			if matchFilter(item, seg) {
+				result = append(result, item)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func clampIndex(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+func findRecursive(node interface{}, name string) []interface{} {
+	var result []interface{}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		if v, ok := n[name]; ok {
+			result = append(result, v)
+		}
+		for _, k := range sortedKeys(n) {
+			result = append(result, findRecursive(n[k], name)...)
+		}
+	case []interface{}:
+		for _, item := range n {
+			result = append(result, findRecursive(item, name)...)
+		}
+	}
+	return result
+}
+
+func matchFilter(item interface{}, seg pathSegment) bool {
+	values := evalPath([]interface{}{item}, seg.filterPath)
+	if seg.filterOp == "" {
+		return len(values) > 0
+	}
+	want := fmt.Sprint(seg.filterValue)
+	for _, v := range values {
+		equal := fmt.Sprint(v) == want
+		if equal == (seg.filterOp == "==") {
+			return true
+		}
+	}
+	return false
+}
+
+func valueString(v interface{}) (string, error) {
+	switch val := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case bool:
+		return strconv.FormatBool(val), nil
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
@@ -0,0 +1,121 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+const testDocument = `{
+  "items": [
+    {"id": "5f6d", "name": "web-1", "status": "ACTIVE", "ram": 4096,
+     "ip_addresses": {"WAN_V4": [{"addr": "103.56.156.11"}], "LAN": [{"addr": "10.20.0.11"}]}},
+    {"id": "9c2e", "name": "web-2", "status": "SHUTOFF", "ram": 2048,
+     "ip_addresses": {"WAN_V4": [], "LAN": [{"addr": "10.20.0.12"}]}},
+    {"id": "d4e5", "name": "db", "status": "ACTIVE", "ram": 8192, "locked": true,
+     "ip_addresses": {"WAN_V4": [{"addr": "103.56.156.13"}], "LAN": [{"addr": "10.20.0.13"}]}}
+  ],
+  "count": 3
+}`
+
+func testDoc(t *testing.T) interface{} {
+	t.Helper()
+	dec := json.NewDecoder(strings.NewReader(testDocument))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		tmpl string
+		want string
+	}{
+		{tmpl: "{.count}", want: "3"},
+		{tmpl: "{$.count}", want: "3"},
+		{tmpl: "{.items[0].name}", want: "web-1"},
+		{tmpl: "{.items[-1].name}", want: "db"},
+		{tmpl: "{.items[*].id}", want: "5f6d 9c2e d4e5"},
+		{tmpl: "{.items[0:2].name}", want: "web-1 web-2"},
+		{tmpl: "{.items[1:].name}", want: "web-2 db"},
+		{tmpl: "{.items[:1].name}", want: "web-1"},
+		{tmpl: "{.items[?(@.status=='ACTIVE')].name}", want: "web-1 db"},
+		{tmpl: "{.items[?(@.status!=\"ACTIVE\")].name}", want: "web-2"},
+		{tmpl: "{.items[?(@.locked)].name}", want: "db"},
+		{tmpl: "{.items[?(@.ram==8192)].id}", want: "d4e5"},
+		{tmpl: "{.items[0].ip_addresses.WAN_V4[0].addr}", want: "103.56.156.11"},
+		{tmpl: "{.items[0]['name']}", want: "web-1"},
+		{tmpl: "{..addr}", want: "10.20.0.11 103.56.156.11 10.20.0.12 10.20.0.13 103.56.156.13"},
+		{tmpl: "{.items[1].ip_addresses.WAN_V4}", want: "[]"},
+		{tmpl: "{.items[0].missing}", want: ""},
+		{tmpl: "count: {.count}", want: "count: 3"},
+		{tmpl: `{range .items[*]}{.id}{"\n"}{end}`, want: "5f6d\n9c2e\nd4e5\n"},
+		{tmpl: `{range .items}{.name}{"\t"}{.status}{"\n"}{end}`, want: "web-1\tACTIVE\nweb-2\tSHUTOFF\ndb\tACTIVE\n"},
+		{tmpl: `{range .items[*]}{.name}={$.count}{' '}{end}`, want: "web-1=3 web-2=3 db=3 "},
+		{tmpl: `{range .items[*]}{range .ip_addresses.LAN[*]}{.addr},{end}{end}`, want: "10.20.0.11,10.20.0.12,10.20.0.13,"},
+		{tmpl: `{"}"}`, want: "}"},
+	}
+	doc := testDoc(t)
+	for _, tc := range tests {
+		jp, err := parseJSONPath(tc.tmpl)
+		if err != nil {
+			t.Errorf("%s: %v", tc.tmpl, err)
+			continue
+		}
+		got, err := jp.execute(doc)
+		if err != nil || got != tc.want {
+			t.Errorf("%s: got %q, %v, want %q", tc.tmpl, got, err, tc.want)
+		}
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	for _, tmpl := range []string{
+		"{.items",
+		"{.items[0}",
+		"{range .items[*]}{.id}",
+		"{.id}{end}",
+		"{range}{end}",
+		`{"\n}`,
+		`{"\q"}`,
+		"{.items[abc]}",
+		"{.items[a:b]}",
+		"{.items[?(@.ram>4096)]}",
+		"{.items[?(.status=='ACTIVE')]}",
+		"{.items.length()}",
+		"{.items .id}",
+		"{..}",
+	} {
+		if _, err := parseJSONPath(tmpl); err == nil {
+			t.Errorf("%s: got no error", tmpl)
+		}
+	}
+}
+
+func TestOutputTemplateError(t *testing.T) {
+	defer func() {
+		outputFormat, out = TableFormat, os.Stdout
+	}()
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	if err := SetFormat("go-template={{index .items 5}}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Output([]string{"ID"}, [][]string{{"5f6d"}}, []map[string]string{{"id": "5f6d"}}); err == nil {
+		t.Errorf("got no error, output %q", buf.String())
+	}
+	if err := SetFormat("go-template={{range .items}}{{.id}} {{end}}"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Output([]string{"ID"}, [][]string{{"5f6d"}}, []map[string]string{{"id": "5f6d"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "5f6d \n" {
+		t.Errorf("got %q, want %q", got, "5f6d \n")
+	}
+}
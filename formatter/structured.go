@@ -0,0 +1,66 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// writeStructured prints obj as JSON or YAML. gobizfly only tags its structs
+// for JSON, so YAML is produced from the JSON document to keep the same keys.
+func writeStructured(obj interface{}) error {
+	b, err := json.MarshalIndent(obj, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the output: %w", err)
+	}
+	if outputFormat == JSONFormat {
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return fmt.Errorf("failed to encode the output: %w", err)
+	}
+	y, err := yaml.Marshal(yamlNumbers(doc))
+	if err != nil {
+		return fmt.Errorf("failed to encode the output: %w", err)
+	}
+	_, err = fmt.Fprint(out, string(y))
+	return err
+}
+
+// yamlNumbers converts the JSON numbers so that integers are not printed in
+// exponent notation
+func yamlNumbers(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, item := range val {
+			val[k] = yamlNumbers(item)
+		}
+	case []interface{}:
+		for i, item := range val {
+			val[i] = yamlNumbers(item)
+		}
+	case json.Number:
+		if i, err := val.Int64(); err == nil {
+			return i
+		}
+		if f, err := val.Float64(); err == nil {
+			return f
+		}
+	}
+	return v
+}
+
+func writeCSV(header []string, data [][]string) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	return w.WriteAll(data)
+}
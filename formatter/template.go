@@ -0,0 +1,125 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+const (
+	// JSONPathFormat prints the fields selected by a JSONPath template
+	JSONPathFormat = "jsonpath"
+	// JSONPathFileFormat reads the JSONPath template from a file
+	JSONPathFileFormat = "jsonpath-file"
+	// GoTemplateFormat renders a Go template
+	GoTemplateFormat = "go-template"
+	// GoTemplateFileFormat reads the Go template from a file
+	GoTemplateFileFormat = "go-template-file"
+)
+
+// TemplateFormats lists the formats taking a template argument, e.g. -o jsonpath='{.items[*].id}'
+var TemplateFormats = []string{JSONPathFormat, JSONPathFileFormat, GoTemplateFormat, GoTemplateFileFormat}
+
+var (
+	jsonPathTemplate *jsonPath
+	goTemplate       *template.Template
+)
+
+// setTemplateFormat parses the argument of a template format
+func setTemplateFormat(format, arg string) error {
+	if format == JSONPathFileFormat || format == GoTemplateFileFormat {
+		b, err := os.ReadFile(arg)
+		if err != nil {
+			return fmt.Errorf("failed to read template file: %w", err)
+		}
+		arg = string(b)
+	}
+	if strings.TrimSpace(arg) == "" {
+		return fmt.Errorf("output format %s requires a template, e.g. %s='...'", format, format)
+	}
+	switch format {
+	case JSONPathFormat, JSONPathFileFormat:
+		jp, err := parseJSONPath(arg)
+		if err != nil {
+			return err
+		}
+		jsonPathTemplate = jp
+		outputFormat = JSONPathFormat
+	default:
+		t, err := template.New("output").Parse(arg)
+		if err != nil {
+			return fmt.Errorf("failed to parse go-template: %w", err)
+		}
+		goTemplate = t
+		outputFormat = GoTemplateFormat
+	}
+	return nil
+}
+
+func isTemplateFormat(format string) bool {
+	for _, f := range TemplateFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTemplate renders obj with the selected JSONPath or Go template.
+func writeTemplate(obj interface{}) error {
+	doc, err := toDocument(obj)
+	if err != nil {
+		return fmt.Errorf("failed to encode the output: %w", err)
+	}
+	var result string
+	if outputFormat == JSONPathFormat {
+		result, err = jsonPathTemplate.execute(doc)
+	} else {
+		var buf bytes.Buffer
+		err = goTemplate.Execute(&buf, doc)
+		result = buf.String()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to render the %s template: %w", outputFormat, err)
+	}
+	if !strings.HasSuffix(result, "\n") {
+		result += "\n"
+	}
+	_, err = fmt.Fprint(out, result)
+	return err
+}
+
+// toDocument converts obj to its JSON document so that templates refer to
+// the fields by their API names. Lists are exposed as ".items".
+func toDocument(obj interface{}) (interface{}, error) {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	if items, ok := doc.([]interface{}); ok {
+		return map[string]interface{}{"items": items}, nil
+	}
+	if doc == nil {
+		return map[string]interface{}{"items": []interface{}{}}, nil
+	}
+	return doc, nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"time"
+)
+
+const redacted = "********"
+
+var (
+	secretHeaders = regexp.MustCompile(`(?im)^(X-Auth-Token|X-Subject-Token|Authorization):.*$`)
+	secretFields  = regexp.MustCompile(`"([A-Za-z_]*(?:password|secret|token))"(\s*):(\s*)"[^"]*"`)
+)
+
+// NewTransport wraps base to print the HTTP requests and responses at the
+// debug level. base is returned as is when the debug level is not enabled.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if !Enabled(DebugLevel) {
+		return base
+	}
+	return &traceTransport{base: base}
+}
+
+type traceTransport struct {
+	base http.RoundTripper
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if dump, err := httputil.DumpRequestOut(req, true); err == nil {
+		Debugf("HTTP request:\n%s", Redact(string(dump)))
+	}
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		Debugf("HTTP request failed after %v: %v", time.Since(start), err)
+		return resp, err
+	}
+	if dump, err := httputil.DumpResponse(resp, true); err == nil {
+		Debugf("HTTP response after %v:\n%s", time.Since(start), Redact(string(dump)))
+	}
+	return resp, nil
+}
+
+// Redact hides the tokens and passwords in an HTTP dump
+func Redact(s string) string {
+	s = secretHeaders.ReplaceAllString(s, "$1: "+redacted)
+	return secretFields.ReplaceAllString(s, `"$1"$2:$3"`+redacted+`"`)
+}
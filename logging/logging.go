@@ -0,0 +1,92 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level is the verbosity of the diagnostics
+type Level int
+
+const (
+	// ErrorLevel prints errors only
+	ErrorLevel Level = iota
+	// WarnLevel prints warnings and errors
+	WarnLevel
+	// InfoLevel prints the status messages of the commands, it is the default
+	InfoLevel
+	// VerboseLevel prints details such as the config file and the token in use
+	VerboseLevel
+	// DebugLevel prints everything, including the HTTP requests and responses
+	DebugLevel
+)
+
+var (
+	mu    sync.Mutex
+	level           = InfoLevel
+	out   io.Writer = os.Stderr
+)
+
+// SetLevel sets the verbosity of the diagnostics
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+}
+
+// GetLevel returns the verbosity of the diagnostics
+func GetLevel() Level {
+	mu.Lock()
+	defer mu.Unlock()
+	return level
+}
+
+// SetOutput changes where the diagnostics are written, stderr by default
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	out = w
+}
+
+// Enabled reports whether messages of the level are printed
+func Enabled(l Level) bool {
+	return GetLevel() >= l
+}
+
+// Errorf prints an error message
+func Errorf(format string, args ...interface{}) {
+	logf(ErrorLevel, "Error: ", format, args...)
+}
+
+// Warnf prints a warning
+func Warnf(format string, args ...interface{}) {
+	logf(WarnLevel, "Warning: ", format, args...)
+}
+
+// Infof prints a status message
+func Infof(format string, args ...interface{}) {
+	logf(InfoLevel, "", format, args...)
+}
+
+// Verbosef prints a message shown with --verbose
+func Verbosef(format string, args ...interface{}) {
+	logf(VerboseLevel, "", format, args...)
+}
+
+// Debugf prints a message shown with --debug
+func Debugf(format string, args ...interface{}) {
+	logf(DebugLevel, "DEBUG: ", format, args...)
+}
+
+func logf(l Level, prefix, format string, args ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if level < l {
+		return
+	}
+	msg := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+	fmt.Fprintln(out, prefix+msg)
+}